@@ -5,7 +5,9 @@ go 1.24.1
 require (
 	butterfly.orx.me/core v0.0.0-20250326150726-e3b4a5d6dff9
 	github.com/modelcontextprotocol/go-sdk v1.2.0
+	golang.org/x/net v0.37.0
 	golang.org/x/oauth2 v0.30.0
+	golang.org/x/text v0.23.0
 	google.golang.org/api v0.228.0
 	pgregory.net/rapid v1.2.0
 )
@@ -29,9 +31,7 @@ require (
 	go.opentelemetry.io/otel/metric v1.35.0 // indirect
 	go.opentelemetry.io/otel/trace v1.35.0 // indirect
 	golang.org/x/crypto v0.36.0 // indirect
-	golang.org/x/net v0.37.0 // indirect
 	golang.org/x/sys v0.31.0 // indirect
-	golang.org/x/text v0.23.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20250313205543-e70fdf4c4cb4 // indirect
 	google.golang.org/grpc v1.71.0 // indirect
 	google.golang.org/protobuf v1.36.6 // indirect
@@ -0,0 +1,47 @@
+package tools
+
+import (
+	"sync"
+	"time"
+)
+
+// resultCache keeps the rendered results of slow tool calls for a fixed time.
+type resultCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	now     func() time.Time
+	entries map[string]cachedResult
+}
+
+// cachedResult is a value stored in a resultCache with its creation time.
+type cachedResult struct {
+	value   string
+	created time.Time
+}
+
+// newResultCache returns an empty cache whose entries expire after ttl.
+func newResultCache(ttl time.Duration) *resultCache {
+	return &resultCache{ttl: ttl, now: time.Now, entries: map[string]cachedResult{}}
+}
+
+// get returns the cached value for key and when it was stored, if it has not expired.
+func (c *resultCache) get(key string) (string, time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return "", time.Time{}, false
+	}
+	if c.now().Sub(entry.created) >= c.ttl {
+		delete(c.entries, key)
+		return "", time.Time{}, false
+	}
+	return entry.value, entry.created, true
+}
+
+// set stores value under key.
+func (c *resultCache) set(key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = cachedResult{value: value, created: c.now()}
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"go.orx.me/mcp/google-workspace/internal/utils"
 	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
 )
 
 // ListDriveFilesInput defines input for list_drive_files tool
@@ -16,6 +18,7 @@ type ListDriveFilesInput struct {
 	Email      string `json:"email" jsonschema:"Email address to access Drive"`
 	MaxResults int64  `json:"maxResults,omitempty" jsonschema:"Maximum number of files to return (default 10)"`
 	FolderID   string `json:"folderId,omitempty" jsonschema:"Optional folder ID to list files from"`
+	DriveID    string `json:"driveId,omitempty" jsonschema:"Optional shared drive ID to limit the listing to"`
 }
 
 // ListDriveFilesOutput defines output for list_drive_files tool
@@ -25,9 +28,19 @@ type ListDriveFilesOutput struct {
 
 // SearchDriveFilesInput defines input for search_drive_files tool
 type SearchDriveFilesInput struct {
-	Email      string `json:"email" jsonschema:"Email address to access Drive"`
-	Query      string `json:"query" jsonschema:"Search query"`
-	MaxResults int64  `json:"maxResults,omitempty" jsonschema:"Maximum number of files to return (default 10)"`
+	Email          string            `json:"email" jsonschema:"Email address to access Drive"`
+	Query          string            `json:"query,omitempty" jsonschema:"Match files whose name contains this text"`
+	FullText       string            `json:"fullText,omitempty" jsonschema:"Match files whose name, description or content contains this text"`
+	MimeType       string            `json:"mimeType,omitempty" jsonschema:"Match files of this exact MIME type (e.g. application/pdf)"`
+	Owner          string            `json:"owner,omitempty" jsonschema:"Match files owned by this email address"`
+	ModifiedAfter  string            `json:"modifiedAfter,omitempty" jsonschema:"Match files modified after this time (RFC3339 or YYYY-MM-DD)"`
+	ModifiedBefore string            `json:"modifiedBefore,omitempty" jsonschema:"Match files modified before this time (RFC3339 or YYYY-MM-DD)"`
+	Starred        bool              `json:"starred,omitempty" jsonschema:"Only match starred files"`
+	SharedWithMe   bool              `json:"sharedWithMe,omitempty" jsonschema:"Only match files in Shared with me"`
+	ParentID       string            `json:"parentId,omitempty" jsonschema:"Only match direct children of this folder ID"`
+	DriveID        string            `json:"driveId,omitempty" jsonschema:"Only search this shared drive (default: My Drive and all shared drives)"`
+	AppProperties  map[string]string `json:"appProperties,omitempty" jsonschema:"Only match files whose custom app properties have all of these key/value pairs"`
+	MaxResults     int64             `json:"maxResults,omitempty" jsonschema:"Maximum number of files to return (default 10)"`
 }
 
 // SearchDriveFilesOutput defines output for search_drive_files tool
@@ -48,9 +61,9 @@ type GetDriveFileOutput struct {
 
 // CreateDriveFolderInput defines input for create_drive_folder tool
 type CreateDriveFolderInput struct {
-	Email      string `json:"email" jsonschema:"Email address to access Drive"`
-	Name       string `json:"name" jsonschema:"Folder name"`
-	ParentID   string `json:"parentId,omitempty" jsonschema:"Parent folder ID (optional)"`
+	Email    string `json:"email" jsonschema:"Email address to access Drive"`
+	Name     string `json:"name" jsonschema:"Folder name"`
+	ParentID string `json:"parentId,omitempty" jsonschema:"Parent folder or shared drive ID (optional)"`
 }
 
 // CreateDriveFolderOutput defines output for create_drive_folder tool
@@ -60,10 +73,10 @@ type CreateDriveFolderOutput struct {
 
 // UploadDriveFileInput defines input for upload_drive_file tool
 type UploadDriveFileInput struct {
-	Email      string `json:"email" jsonschema:"Email address to access Drive"`
-	FilePath   string `json:"filePath" jsonschema:"Local file path to upload"`
-	Name       string `json:"name,omitempty" jsonschema:"Name for the file in Drive (uses filename if not specified)"`
-	ParentID   string `json:"parentId,omitempty" jsonschema:"Parent folder ID (optional)"`
+	Email    string `json:"email" jsonschema:"Email address to access Drive"`
+	FilePath string `json:"filePath" jsonschema:"Local file path to upload"`
+	Name     string `json:"name,omitempty" jsonschema:"Name for the file in Drive (uses filename if not specified)"`
+	ParentID string `json:"parentId,omitempty" jsonschema:"Parent folder or shared drive ID (optional)"`
 }
 
 // UploadDriveFileOutput defines output for upload_drive_file tool
@@ -73,10 +86,17 @@ type UploadDriveFileOutput struct {
 
 // ShareDriveFileInput defines input for share_drive_file tool
 type ShareDriveFileInput struct {
-	Email      string `json:"email" jsonschema:"Email address to access Drive"`
-	FileID     string `json:"fileId" jsonschema:"File ID to share"`
-	UserEmail  string `json:"userEmail" jsonschema:"Email address to share with"`
-	Role       string `json:"role,omitempty" jsonschema:"Permission role: reader, writer, commenter (default: reader)"`
+	Email                string `json:"email" jsonschema:"Email address to access Drive"`
+	FileID               string `json:"fileId" jsonschema:"File ID to share"`
+	UserEmail            string `json:"userEmail,omitempty" jsonschema:"Email address of the user or group to share with (required for user and group types)"`
+	Type                 string `json:"type,omitempty" jsonschema:"Grantee type: user, group, domain, or anyone for link sharing (default: user)"`
+	Domain               string `json:"domain,omitempty" jsonschema:"Domain to share with (required for the domain type)"`
+	Role                 string `json:"role,omitempty" jsonschema:"Permission role: reader, commenter, writer, fileOrganizer, organizer, owner (default: reader)"`
+	AllowFileDiscovery   bool   `json:"allowFileDiscovery,omitempty" jsonschema:"Let domain or anyone grantees find the file through search instead of needing the link"`
+	ExpirationTime       string `json:"expirationTime,omitempty" jsonschema:"When user or group access expires, in RFC3339 format"`
+	EmailMessage         string `json:"emailMessage,omitempty" jsonschema:"Custom message to include in the notification email"`
+	SuppressNotification bool   `json:"suppressNotification,omitempty" jsonschema:"Do not send a notification email to user or group grantees"`
+	TransferOwnership    bool   `json:"transferOwnership,omitempty" jsonschema:"Must be true when role is owner; makes the grantee the new owner"`
 }
 
 // ShareDriveFileOutput defines output for share_drive_file tool
@@ -96,15 +116,16 @@ func ListDriveFiles(ctx context.Context, req *mcp.CallToolRequest, input ListDri
 		maxResults = 10
 	}
 
-	query := "trashed = false"
+	query := &driveQuery{}
 	if input.FolderID != "" {
-		query = fmt.Sprintf("'%s' in parents and trashed = false", input.FolderID)
+		query.InParents(input.FolderID)
 	}
+	query.Trashed(false)
 
-	files, err := srv.Files.List().
+	files, err := allDrivesList(srv.Files.List(), input.DriveID).
 		PageSize(maxResults).
-		Q(query).
-		Fields("files(id, name, mimeType, modifiedTime, size, webViewLink)").
+		Q(query.String()).
+		Fields("files(id, name, mimeType, modifiedTime, size, webViewLink, shortcutDetails)").
 		Do()
 	if err != nil {
 		return nil, ListDriveFilesOutput{}, err
@@ -116,12 +137,7 @@ func ListDriveFiles(ctx context.Context, req *mcp.CallToolRequest, input ListDri
 	} else {
 		resp.WriteString("Files:\n")
 		for _, file := range files.Files {
-			fileType := "File"
-			if file.MimeType == "application/vnd.google-apps.folder" {
-				fileType = "Folder"
-			}
-			resp.WriteString(fmt.Sprintf("[%s] %s\n  ID: %s\n  Type: %s\n  Modified: %s\n  Link: %s\n\n",
-				fileType, file.Name, file.Id, file.MimeType, file.ModifiedTime, file.WebViewLink))
+			resp.WriteString(formatDriveListEntry(file))
 		}
 	}
 
@@ -130,6 +146,11 @@ func ListDriveFiles(ctx context.Context, req *mcp.CallToolRequest, input ListDri
 
 // SearchDriveFiles handles the search_drive_files tool call
 func SearchDriveFiles(ctx context.Context, req *mcp.CallToolRequest, input SearchDriveFilesInput) (*mcp.CallToolResult, SearchDriveFilesOutput, error) {
+	query, err := buildSearchDriveQuery(input)
+	if err != nil {
+		return nil, SearchDriveFilesOutput{}, err
+	}
+
 	srv, err := utils.NewDriveClient(input.Email)
 	if err != nil {
 		return nil, SearchDriveFilesOutput{}, err
@@ -140,13 +161,10 @@ func SearchDriveFiles(ctx context.Context, req *mcp.CallToolRequest, input Searc
 		maxResults = 10
 	}
 
-	// Build search query
-	query := fmt.Sprintf("name contains '%s' and trashed = false", input.Query)
-
-	files, err := srv.Files.List().
+	files, err := allDrivesList(srv.Files.List(), input.DriveID).
 		PageSize(maxResults).
 		Q(query).
-		Fields("files(id, name, mimeType, modifiedTime, size, webViewLink)").
+		Fields("files(id, name, mimeType, modifiedTime, size, webViewLink, shortcutDetails)").
 		Do()
 	if err != nil {
 		return nil, SearchDriveFilesOutput{}, err
@@ -154,22 +172,69 @@ func SearchDriveFiles(ctx context.Context, req *mcp.CallToolRequest, input Searc
 
 	var resp strings.Builder
 	if len(files.Files) == 0 {
-		resp.WriteString(fmt.Sprintf("No files found matching '%s'.\n", input.Query))
+		resp.WriteString(fmt.Sprintf("No files found matching %s.\n", query))
 	} else {
-		resp.WriteString(fmt.Sprintf("Found %d file(s) matching '%s':\n\n", len(files.Files), input.Query))
+		resp.WriteString(fmt.Sprintf("Found %d file(s) matching %s:\n\n", len(files.Files), query))
 		for _, file := range files.Files {
-			fileType := "File"
-			if file.MimeType == "application/vnd.google-apps.folder" {
-				fileType = "Folder"
-			}
-			resp.WriteString(fmt.Sprintf("[%s] %s\n  ID: %s\n  Type: %s\n  Modified: %s\n  Link: %s\n\n",
-				fileType, file.Name, file.Id, file.MimeType, file.ModifiedTime, file.WebViewLink))
+			resp.WriteString(formatDriveListEntry(file))
 		}
 	}
 
 	return nil, SearchDriveFilesOutput{Files: resp.String()}, nil
 }
 
+// buildSearchDriveQuery turns the structured search_drive_files filters into a
+// Drive query expression. Trashed files are always excluded.
+func buildSearchDriveQuery(input SearchDriveFilesInput) (string, error) {
+	query := &driveQuery{}
+	if input.Query != "" {
+		query.NameContains(input.Query)
+	}
+	if input.FullText != "" {
+		query.FullTextContains(input.FullText)
+	}
+	if input.MimeType != "" {
+		query.MimeType(input.MimeType)
+	}
+	if input.Owner != "" {
+		query.Owner(input.Owner)
+	}
+	if input.ModifiedAfter != "" {
+		t, err := parseDriveQueryTime("modifiedAfter", input.ModifiedAfter)
+		if err != nil {
+			return "", err
+		}
+		query.ModifiedAfter(t)
+	}
+	if input.ModifiedBefore != "" {
+		t, err := parseDriveQueryTime("modifiedBefore", input.ModifiedBefore)
+		if err != nil {
+			return "", err
+		}
+		query.ModifiedBefore(t)
+	}
+	if input.Starred {
+		query.Starred()
+	}
+	if input.SharedWithMe {
+		query.SharedWithMe()
+	}
+	if input.ParentID != "" {
+		query.InParents(input.ParentID)
+	}
+	keys := make([]string, 0, len(input.AppProperties))
+	for k := range input.AppProperties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		query.AppProperty(k, input.AppProperties[k])
+	}
+	query.Trashed(false)
+
+	return query.String(), nil
+}
+
 // GetDriveFile handles the get_drive_file tool call
 func GetDriveFile(ctx context.Context, req *mcp.CallToolRequest, input GetDriveFileInput) (*mcp.CallToolResult, GetDriveFileOutput, error) {
 	srv, err := utils.NewDriveClient(input.Email)
@@ -177,30 +242,74 @@ func GetDriveFile(ctx context.Context, req *mcp.CallToolRequest, input GetDriveF
 		return nil, GetDriveFileOutput{}, err
 	}
 
+	fields := googleapi.Field("id, name, mimeType, modifiedTime, size, webViewLink, description, owners, permissions, driveId, headRevisionId, " +
+		"starred, folderColorRgb, appProperties, shortcutDetails")
 	file, err := srv.Files.Get(input.FileID).
-		Fields("id, name, mimeType, modifiedTime, size, webViewLink, description, owners, permissions").
+		SupportsAllDrives(true).
+		Fields(fields).
 		Do()
 	if err != nil {
 		return nil, GetDriveFileOutput{}, err
 	}
 
 	var resp strings.Builder
+	// Shortcuts are resolved to their target so callers see the real file.
+	if file.ShortcutDetails != nil && file.ShortcutDetails.TargetId != "" {
+		resp.WriteString(fmt.Sprintf("Shortcut %s (%s) resolved to its target.\n", file.Name, file.Id))
+		file, err = srv.Files.Get(file.ShortcutDetails.TargetId).
+			SupportsAllDrives(true).
+			Fields(fields).
+			Do()
+		if err != nil {
+			return nil, GetDriveFileOutput{}, fmt.Errorf("failed to resolve shortcut target: %w", err)
+		}
+	}
+
 	resp.WriteString("File Information:\n")
 	resp.WriteString(fmt.Sprintf("  Name: %s\n", file.Name))
 	resp.WriteString(fmt.Sprintf("  ID: %s\n", file.Id))
 	resp.WriteString(fmt.Sprintf("  Type: %s\n", file.MimeType))
 	resp.WriteString(fmt.Sprintf("  Modified: %s\n", file.ModifiedTime))
+	if file.HeadRevisionId != "" {
+		resp.WriteString(fmt.Sprintf("  Head Revision ID: %s\n", file.HeadRevisionId))
+	}
 	if file.Size > 0 {
 		resp.WriteString(fmt.Sprintf("  Size: %d bytes\n", file.Size))
 	}
 	if file.Description != "" {
 		resp.WriteString(fmt.Sprintf("  Description: %s\n", file.Description))
 	}
+	if file.Starred {
+		resp.WriteString("  Starred: true\n")
+	}
+	if file.MimeType == driveFolderMimeType && file.FolderColorRgb != "" {
+		resp.WriteString(fmt.Sprintf("  Folder color: %s\n", file.FolderColorRgb))
+	}
 	resp.WriteString(fmt.Sprintf("  Link: %s\n", file.WebViewLink))
-	
+
 	if len(file.Owners) > 0 {
 		resp.WriteString(fmt.Sprintf("  Owner: %s\n", file.Owners[0].EmailAddress))
 	}
+	if file.DriveId != "" {
+		resp.WriteString(fmt.Sprintf("  Shared Drive ID: %s\n", file.DriveId))
+	}
+	if len(file.AppProperties) > 0 {
+		resp.WriteString("  App properties:\n")
+		keys := make([]string, 0, len(file.AppProperties))
+		for k := range file.AppProperties {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			resp.WriteString(fmt.Sprintf("    %s: %s\n", k, file.AppProperties[k]))
+		}
+	}
+	if len(file.Permissions) > 0 {
+		resp.WriteString("  Shared with:\n")
+		for _, p := range file.Permissions {
+			resp.WriteString(fmt.Sprintf("    - %s (%s): %s\n", describeDrivePermission(p), p.Type, p.Role))
+		}
+	}
 
 	return nil, GetDriveFileOutput{FileInfo: resp.String()}, nil
 }
@@ -222,6 +331,7 @@ func CreateDriveFolder(ctx context.Context, req *mcp.CallToolRequest, input Crea
 	}
 
 	folder, err := srv.Files.Create(fileMetadata).
+		SupportsAllDrives(true).
 		Fields("id, name, webViewLink").
 		Do()
 	if err != nil {
@@ -270,6 +380,7 @@ func UploadDriveFile(ctx context.Context, req *mcp.CallToolRequest, input Upload
 
 	uploadedFile, err := srv.Files.Create(fileMetadata).
 		Media(file).
+		SupportsAllDrives(true).
 		Fields("id, name, mimeType, size, webViewLink").
 		Do()
 	if err != nil {
@@ -284,9 +395,9 @@ func UploadDriveFile(ctx context.Context, req *mcp.CallToolRequest, input Upload
 
 // ShareDriveFile handles the share_drive_file tool call
 func ShareDriveFile(ctx context.Context, req *mcp.CallToolRequest, input ShareDriveFileInput) (*mcp.CallToolResult, ShareDriveFileOutput, error) {
-	srv, err := utils.NewDriveClient(input.Email)
-	if err != nil {
-		return nil, ShareDriveFileOutput{}, err
+	permType := input.Type
+	if permType == "" {
+		permType = "user"
 	}
 
 	role := input.Role
@@ -294,38 +405,55 @@ func ShareDriveFile(ctx context.Context, req *mcp.CallToolRequest, input ShareDr
 		role = "reader"
 	}
 
-	// Validate role
-	validRoles := map[string]bool{
-		"reader":    true,
-		"writer":    true,
-		"commenter": true,
-		"owner":     true,
+	permission, err := newDrivePermission(permType, role, input.UserEmail, input.Domain, input.AllowFileDiscovery, input.ExpirationTime)
+	if err != nil {
+		return nil, ShareDriveFileOutput{}, err
 	}
-	if !validRoles[role] {
-		return nil, ShareDriveFileOutput{}, fmt.Errorf("invalid role: %s (must be reader, writer, commenter, or owner)", role)
+	if role == "owner" && !input.TransferOwnership {
+		return nil, ShareDriveFileOutput{}, fmt.Errorf("sharing with the owner role requires transferOwnership to be true")
 	}
 
-	permission := &drive.Permission{
-		Type:         "user",
-		Role:         role,
-		EmailAddress: input.UserEmail,
+	// Notification emails only go to user and group grantees, and Drive
+	// always notifies the new owner of an ownership transfer.
+	notify := (permType == "user" || permType == "group") && !input.SuppressNotification
+	if input.TransferOwnership && input.SuppressNotification {
+		return nil, ShareDriveFileOutput{}, fmt.Errorf("notifications cannot be suppressed when transferring ownership")
+	}
+	if input.EmailMessage != "" && !notify {
+		return nil, ShareDriveFileOutput{}, fmt.Errorf("emailMessage requires a notification email to a user or group")
 	}
 
-	_, err = srv.Permissions.Create(input.FileID, permission).
-		SendNotificationEmail(true).
-		Do()
+	srv, err := utils.NewDriveClient(input.Email)
+	if err != nil {
+		return nil, ShareDriveFileOutput{}, err
+	}
+
+	call := srv.Permissions.Create(input.FileID, permission).
+		TransferOwnership(input.TransferOwnership).
+		SupportsAllDrives(true)
+	if permType == "user" || permType == "group" {
+		call = call.SendNotificationEmail(notify)
+	}
+	if input.EmailMessage != "" {
+		call = call.EmailMessage(input.EmailMessage)
+	}
+
+	_, err = call.Do()
 	if err != nil {
 		return nil, ShareDriveFileOutput{}, fmt.Errorf("failed to share file: %w", err)
 	}
 
 	// Get file info
-	file, err := srv.Files.Get(input.FileID).Fields("name, webViewLink").Do()
+	file, err := srv.Files.Get(input.FileID).SupportsAllDrives(true).Fields("name, webViewLink").Do()
 	if err != nil {
 		return nil, ShareDriveFileOutput{}, fmt.Errorf("failed to get file info: %w", err)
 	}
 
 	resp := fmt.Sprintf("File shared successfully:\n  File: %s\n  Shared with: %s\n  Role: %s\n  Link: %s",
-		file.Name, input.UserEmail, role, file.WebViewLink)
+		file.Name, describeDrivePermission(permission), role, file.WebViewLink)
+	if permission.ExpirationTime != "" {
+		resp += fmt.Sprintf("\n  Expires: %s", permission.ExpirationTime)
+	}
 
 	return nil, ShareDriveFileOutput{Result: resp}, nil
 }
@@ -339,7 +467,7 @@ func RegisterDriveTools(server *mcp.Server) {
 
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "search_drive_files",
-		Description: "Search for files in Google Drive",
+		Description: "Search for files in Google Drive by name, full text, MIME type, owner, modified time, starred, shared-with-me or parent folder",
 	}, SearchDriveFiles)
 
 	mcp.AddTool(server, &mcp.Tool{
@@ -359,6 +487,209 @@ func RegisterDriveTools(server *mcp.Server) {
 
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "share_drive_file",
-		Description: "Share a Drive file with another user",
+		Description: "Share a Drive file with a user, group, domain, or anyone with the link",
 	}, ShareDriveFile)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "download_drive_file",
+		Description: "Download a Drive file or folder to a local path inside the sandbox directory",
+	}, DownloadDriveFile)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_shared_drives",
+		Description: "List shared drives the user is a member of, or every shared drive in the domain as an administrator",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, ListSharedDrives)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "create_shared_drive",
+		Description: "Create a new shared drive",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(false)},
+	}, CreateSharedDrive)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "rename_shared_drive",
+		Description: "Rename a shared drive",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(false), IdempotentHint: true},
+	}, RenameSharedDrive)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "hide_shared_drive",
+		Description: "Hide a shared drive from the default view or show it again",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(false), IdempotentHint: true},
+	}, HideSharedDrive)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_shared_drive_members",
+		Description: "List members of a shared drive and their roles",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, ListSharedDriveMembers)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "add_shared_drive_member",
+		Description: "Add a user or group to a shared drive",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(false)},
+	}, AddSharedDriveMember)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "remove_shared_drive_member",
+		Description: "Remove a user or group from a shared drive",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(true), IdempotentHint: true},
+	}, RemoveSharedDriveMember)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "rename_drive_file",
+		Description: "Rename a file or folder in Google Drive",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(false), IdempotentHint: true},
+	}, RenameDriveFile)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "move_drive_file",
+		Description: "Move a file or folder into another Drive folder",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(false), IdempotentHint: true},
+	}, MoveDriveFile)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "copy_drive_file",
+		Description: "Copy a Drive file, including Google Docs, Sheets and Slides",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(false)},
+	}, CopyDriveFile)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "trash_drive_file",
+		Description: "Move a Drive file or folder to the trash",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(true), IdempotentHint: true},
+	}, TrashDriveFile)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "restore_drive_file",
+		Description: "Restore a Drive file or folder from the trash",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(false), IdempotentHint: true},
+	}, RestoreDriveFile)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "delete_drive_file",
+		Description: "Permanently delete a Drive file or folder, bypassing the trash",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(true), IdempotentHint: true},
+	}, DeleteDriveFile)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "empty_drive_trash",
+		Description: "Permanently delete every file in the Drive trash",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(true), IdempotentHint: true},
+	}, EmptyDriveTrash)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_drive_permissions",
+		Description: "List who a Drive file is shared with, including link sharing and expirations",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, ListDrivePermissions)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "update_drive_permission",
+		Description: "Change the role or expiration of a Drive permission, or transfer ownership",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(true), IdempotentHint: true},
+	}, UpdateDrivePermission)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "delete_drive_permission",
+		Description: "Remove a permission from a Drive file to stop sharing it",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(true), IdempotentHint: true},
+	}, DeleteDrivePermission)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "audit_drive_sharing",
+		Description: "Audit a user's or every domain user's Drive files for external, link, or over-privileged sharing, optionally revoking it",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(true)},
+	}, AuditDriveSharing)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_drive_revisions",
+		Description: "List the revisions of a Drive file with author and modification time",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, ListDriveRevisions)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_drive_revision",
+		Description: "Fetch or export the content of a specific Drive file revision",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, GetDriveRevision)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "pin_drive_revision",
+		Description: "Pin a Drive file revision so it is kept forever, or unpin it",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(false), IdempotentHint: true},
+	}, PinDriveRevision)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "restore_drive_revision",
+		Description: "Restore an old revision of a Drive file by uploading its content as the new head revision",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(false)},
+	}, RestoreDriveRevision)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_drive_comments",
+		Description: "List comments on a Drive file with their quoted text and replies",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, ListDriveComments)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "create_drive_comment",
+		Description: "Add a comment to a Drive file",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(false)},
+	}, CreateDriveComment)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "reply_drive_comment",
+		Description: "Reply to a comment on a Drive file",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(false)},
+	}, ReplyDriveComment)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "resolve_drive_comment",
+		Description: "Resolve or reopen a comment on a Drive file",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(false)},
+	}, ResolveDriveComment)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "update_drive_file_content",
+		Description: "Replace the content of an existing Drive file as a new revision, rejecting the update if the file changed since it was last read",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(false)},
+	}, UpdateDriveFileContent)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_drive_folder_tree",
+		Description: "List a Drive folder's contents recursively, with types and sizes, up to a depth limit",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, GetDriveFolderTree)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "sync_drive_folder",
+		Description: "One-way sync between a Drive folder and a local sandbox directory; reports the plan and only changes files when apply is true",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(true)},
+	}, SyncDriveFolder)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_drive_storage_quota",
+		Description: "Report Drive storage quota usage for a user, or for every user in a domain with totals",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, GetDriveStorageQuota)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_drive_usage_breakdown",
+		Description: "Aggregate a user's Drive file sizes by MIME type, owner and top-level folder, with the total of the files they own",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, GetDriveUsageBreakdown)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "create_drive_shortcut",
+		Description: "Create a Drive shortcut pointing to a file or folder",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(false)},
+	}, CreateDriveShortcut)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "set_drive_file_metadata",
+		Description: "Set the starred state, description, folder color and custom app properties of a Drive file",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(false), IdempotentHint: true},
+	}, SetDriveFileMetadata)
 }
@@ -0,0 +1,248 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.orx.me/mcp/google-workspace/internal/utils"
+	admin "google.golang.org/api/admin/directory/v1"
+	"google.golang.org/api/drive/v3"
+)
+
+// AuditDriveSharingInput defines input for audit_drive_sharing tool
+type AuditDriveSharingInput struct {
+	Email           string   `json:"email,omitempty" jsonschema:"User whose owned files to audit (required unless allUsers is set)"`
+	AllUsers        bool     `json:"allUsers,omitempty" jsonschema:"Audit every user in the directory domain instead of a single user"`
+	Domain          string   `json:"domain,omitempty" jsonschema:"Directory domain whose users to audit when allUsers is set"`
+	InternalDomains []string `json:"internalDomains,omitempty" jsonschema:"Domains considered internal (default: the domain of email, or domain when allUsers is set)"`
+	Revoke          bool     `json:"revoke,omitempty" jsonschema:"Delete every offending permission that is found (default false: report only)"`
+	MaxFilesPerUser int64    `json:"maxFilesPerUser,omitempty" jsonschema:"Maximum number of files to inspect per user (default 1000)"`
+}
+
+// AuditDriveSharingOutput defines output for audit_drive_sharing tool
+type AuditDriveSharingOutput struct {
+	Report string `json:"report" jsonschema:"Files shared externally, by link, or with over-privileged writers"`
+}
+
+// Reasons a permission is flagged by the sharing audit.
+const (
+	sharingFindingAnyone   = "anyone with link"
+	sharingFindingExternal = "external"
+	sharingFindingWriter   = "over-privileged writer"
+)
+
+// errStopPaging ends a Pages iteration early once enough results were seen.
+var errStopPaging = errors.New("stop paging")
+
+// writerRoles are the roles that let a grantee change a file's content.
+var writerRoles = map[string]bool{
+	"writer":        true,
+	"fileOrganizer": true,
+	"organizer":     true,
+	"owner":         true,
+}
+
+// emailDomain returns the lower-cased domain part of an email address.
+func emailDomain(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return ""
+	}
+	return strings.ToLower(email[at+1:])
+}
+
+// classifyDrivePermission returns the reasons a permission should be flagged
+// by the sharing audit, or nil when it only grants access inside the given
+// internal domains. Writers are over-privileged when the grant reaches beyond
+// named internal accounts, i.e. to link holders, a whole domain, or outsiders.
+func classifyDrivePermission(p *drive.Permission, internal map[string]bool) []string {
+	var reasons []string
+	broad := false
+
+	switch p.Type {
+	case "anyone":
+		reasons = append(reasons, sharingFindingAnyone)
+		broad = true
+	case "domain":
+		if !internal[strings.ToLower(p.Domain)] {
+			reasons = append(reasons, sharingFindingExternal)
+		}
+		broad = true
+	case "user", "group":
+		if d := emailDomain(p.EmailAddress); d != "" && !internal[d] {
+			reasons = append(reasons, sharingFindingExternal)
+			broad = true
+		}
+	}
+
+	if broad && writerRoles[p.Role] {
+		reasons = append(reasons, sharingFindingWriter)
+	}
+	return reasons
+}
+
+// driveSharingAudit accumulates the results of one audit_drive_sharing call.
+type driveSharingAudit struct {
+	internal map[string]bool
+	revoke   bool
+	maxFiles int64
+
+	report   strings.Builder
+	notes    strings.Builder
+	files    int
+	findings int
+	revoked  int
+	failures int
+}
+
+// auditUser inspects the files owned by email and records offending permissions.
+func (a *driveSharingAudit) auditUser(ctx context.Context, email string) error {
+	srv, err := utils.NewDriveClient(email)
+	if err != nil {
+		return err
+	}
+
+	query := (&driveQuery{}).Owner(email).Trashed(false)
+	inspected := int64(0)
+	truncated := false
+	err = srv.Files.List().
+		Q(query.String()).
+		PageSize(100).
+		Fields("nextPageToken, files(id, name, webViewLink, permissions(id, type, role, emailAddress, domain, displayName))").
+		Pages(ctx, func(page *drive.FileList) error {
+			for _, file := range page.Files {
+				if inspected >= a.maxFiles {
+					truncated = true
+					return errStopPaging
+				}
+				inspected++
+				a.files++
+				a.auditFile(srv, email, file)
+			}
+			return nil
+		})
+	if err != nil && err != errStopPaging {
+		return err
+	}
+	if truncated {
+		a.notes.WriteString(fmt.Sprintf("- %s: stopped after %d files\n", email, a.maxFiles))
+	}
+	return nil
+}
+
+// auditFile records every offending permission on file, revoking it if asked.
+func (a *driveSharingAudit) auditFile(srv *drive.Service, owner string, file *drive.File) {
+	for _, p := range file.Permissions {
+		if p.Role == "owner" {
+			continue
+		}
+		reasons := classifyDrivePermission(p, a.internal)
+		if len(reasons) == 0 {
+			continue
+		}
+		a.findings++
+
+		status := ""
+		if a.revoke {
+			if err := srv.Permissions.Delete(file.Id, p.Id).SupportsAllDrives(true).Do(); err != nil {
+				a.failures++
+				status = fmt.Sprintf("\n  Revoke failed: %v", err)
+			} else {
+				a.revoked++
+				status = "\n  Revoked"
+			}
+		}
+
+		a.report.WriteString(fmt.Sprintf("- %s (%s)\n  Owner: %s\n  Shared with: %s (%s)\n  Findings: %s\n  Permission ID: %s\n  Link: %s%s\n",
+			file.Name, file.Id, owner, describeDrivePermission(p), p.Role, strings.Join(reasons, ", "), p.Id, file.WebViewLink, status))
+	}
+}
+
+// AuditDriveSharing handles the audit_drive_sharing tool call
+func AuditDriveSharing(ctx context.Context, req *mcp.CallToolRequest, input AuditDriveSharingInput) (*mcp.CallToolResult, AuditDriveSharingOutput, error) {
+	if input.AllUsers && input.Domain == "" {
+		return nil, AuditDriveSharingOutput{}, fmt.Errorf("domain is required when allUsers is set")
+	}
+	if !input.AllUsers && input.Email == "" {
+		return nil, AuditDriveSharingOutput{}, fmt.Errorf("email is required unless allUsers is set")
+	}
+
+	internal := map[string]bool{}
+	for _, d := range input.InternalDomains {
+		internal[strings.ToLower(d)] = true
+	}
+	if len(internal) == 0 {
+		if input.AllUsers {
+			internal[strings.ToLower(input.Domain)] = true
+		} else {
+			internal[emailDomain(input.Email)] = true
+		}
+	}
+
+	maxFiles := input.MaxFilesPerUser
+	if maxFiles == 0 {
+		maxFiles = 1000
+	}
+
+	audit := &driveSharingAudit{
+		internal: internal,
+		revoke:   input.Revoke,
+		maxFiles: maxFiles,
+	}
+
+	users := []string{input.Email}
+	if input.AllUsers {
+		client, err := utils.DefaultClient()
+		if err != nil {
+			return nil, AuditDriveSharingOutput{}, err
+		}
+		users = nil
+		err = client.Users.List().Domain(input.Domain).Pages(ctx, func(page *admin.Users) error {
+			for _, user := range page.Users {
+				if !user.Suspended {
+					users = append(users, user.PrimaryEmail)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, AuditDriveSharingOutput{}, fmt.Errorf("failed to list users: %w", err)
+		}
+	}
+
+	var userErrors strings.Builder
+	for i, email := range users {
+		notifyProgress(ctx, req, float64(i), float64(len(users)), "Auditing "+email)
+		if err := audit.auditUser(ctx, email); err != nil {
+			if !input.AllUsers {
+				return nil, AuditDriveSharingOutput{}, fmt.Errorf("failed to audit %s: %w", email, err)
+			}
+			userErrors.WriteString(fmt.Sprintf("- %s: %v\n", email, err))
+		}
+	}
+
+	var resp strings.Builder
+	resp.WriteString(fmt.Sprintf("Sharing audit: %d user(s), %d file(s) inspected, %d finding(s)\n", len(users), audit.files, audit.findings))
+	if input.Revoke {
+		resp.WriteString(fmt.Sprintf("Revoked: %d, failed: %d\n", audit.revoked, audit.failures))
+	}
+	resp.WriteString("\n")
+	if audit.findings == 0 {
+		resp.WriteString("No externally or publicly shared files found.\n")
+	} else {
+		resp.WriteString(audit.report.String())
+	}
+	if audit.notes.Len() > 0 {
+		resp.WriteString("\nIncomplete audits (raise maxFilesPerUser to inspect more):\n")
+		resp.WriteString(audit.notes.String())
+	}
+	if userErrors.Len() > 0 {
+		resp.WriteString("\nUsers that could not be audited:\n")
+		resp.WriteString(userErrors.String())
+	}
+
+	return nil, AuditDriveSharingOutput{Report: resp.String()}, nil
+}
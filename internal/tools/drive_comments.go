@@ -0,0 +1,226 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.orx.me/mcp/google-workspace/internal/utils"
+	"google.golang.org/api/drive/v3"
+)
+
+// ListDriveCommentsInput defines input for list_drive_comments tool
+type ListDriveCommentsInput struct {
+	Email           string `json:"email" jsonschema:"Email address to access Drive"`
+	FileID          string `json:"fileId" jsonschema:"File ID whose comments to list"`
+	IncludeResolved bool   `json:"includeResolved,omitempty" jsonschema:"Include resolved comments (default false: open comments only)"`
+}
+
+// ListDriveCommentsOutput defines output for list_drive_comments tool
+type ListDriveCommentsOutput struct {
+	Comments string `json:"comments" jsonschema:"List of comments with quoted text and replies"`
+}
+
+// CreateDriveCommentInput defines input for create_drive_comment tool
+type CreateDriveCommentInput struct {
+	Email      string `json:"email" jsonschema:"Email address to access Drive"`
+	FileID     string `json:"fileId" jsonschema:"File ID to comment on"`
+	Content    string `json:"content" jsonschema:"Comment text"`
+	QuotedText string `json:"quotedText,omitempty" jsonschema:"Text of the document the comment refers to"`
+}
+
+// CreateDriveCommentOutput defines output for create_drive_comment tool
+type CreateDriveCommentOutput struct {
+	Result string `json:"result" jsonschema:"Created comment information"`
+}
+
+// ReplyDriveCommentInput defines input for reply_drive_comment tool
+type ReplyDriveCommentInput struct {
+	Email     string `json:"email" jsonschema:"Email address to access Drive"`
+	FileID    string `json:"fileId" jsonschema:"File ID"`
+	CommentID string `json:"commentId" jsonschema:"Comment ID to reply to (from list_drive_comments)"`
+	Content   string `json:"content" jsonschema:"Reply text"`
+}
+
+// ReplyDriveCommentOutput defines output for reply_drive_comment tool
+type ReplyDriveCommentOutput struct {
+	Result string `json:"result" jsonschema:"Created reply information"`
+}
+
+// ResolveDriveCommentInput defines input for resolve_drive_comment tool
+type ResolveDriveCommentInput struct {
+	Email     string `json:"email" jsonschema:"Email address to access Drive"`
+	FileID    string `json:"fileId" jsonschema:"File ID"`
+	CommentID string `json:"commentId" jsonschema:"Comment ID (from list_drive_comments)"`
+	Resolved  bool   `json:"resolved" jsonschema:"Set true to resolve the comment, false to reopen it"`
+	Content   string `json:"content,omitempty" jsonschema:"Optional reply text posted along with the change"`
+}
+
+// ResolveDriveCommentOutput defines output for resolve_drive_comment tool
+type ResolveDriveCommentOutput struct {
+	Result string `json:"result" jsonschema:"Resolve result"`
+}
+
+// driveCommentFields selects the comment fields shown by the comment tools.
+const driveCommentFields = "id, content, createdTime, modifiedTime, resolved, quotedFileContent, author(displayName, emailAddress), " +
+	"replies(id, content, createdTime, action, deleted, author(displayName, emailAddress))"
+
+// describeDriveUser returns a display name with the email address when known.
+func describeDriveUser(u *drive.User) string {
+	if u == nil {
+		return "unknown"
+	}
+	if u.EmailAddress != "" {
+		return fmt.Sprintf("%s <%s>", u.DisplayName, u.EmailAddress)
+	}
+	return u.DisplayName
+}
+
+// formatDriveComment renders a comment and its replies.
+func formatDriveComment(c *drive.Comment) string {
+	var b strings.Builder
+	status := "open"
+	if c.Resolved {
+		status = "resolved"
+	}
+	b.WriteString(fmt.Sprintf("- Comment %s (%s)\n  Author: %s\n  Created: %s\n", c.Id, status, describeDriveUser(c.Author), c.CreatedTime))
+	if c.QuotedFileContent != nil && c.QuotedFileContent.Value != "" {
+		b.WriteString(fmt.Sprintf("  Quoted text: %q\n", c.QuotedFileContent.Value))
+	}
+	b.WriteString(fmt.Sprintf("  Content: %s\n", c.Content))
+	for _, r := range c.Replies {
+		if r.Deleted {
+			continue
+		}
+		b.WriteString(fmt.Sprintf("  - Reply %s by %s at %s", r.Id, describeDriveUser(r.Author), r.CreatedTime))
+		if r.Action != "" {
+			b.WriteString(fmt.Sprintf(" [%s]", r.Action))
+		}
+		if r.Content != "" {
+			b.WriteString(": " + r.Content)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// ListDriveComments handles the list_drive_comments tool call
+func ListDriveComments(ctx context.Context, req *mcp.CallToolRequest, input ListDriveCommentsInput) (*mcp.CallToolResult, ListDriveCommentsOutput, error) {
+	srv, err := utils.NewDriveClient(input.Email)
+	if err != nil {
+		return nil, ListDriveCommentsOutput{}, err
+	}
+
+	var resp strings.Builder
+	count, resolved := 0, 0
+	err = srv.Comments.List(input.FileID).
+		PageSize(100).
+		Fields("nextPageToken, comments("+driveCommentFields+")").
+		Pages(ctx, func(page *drive.CommentList) error {
+			for _, c := range page.Comments {
+				if c.Resolved {
+					resolved++
+					if !input.IncludeResolved {
+						continue
+					}
+				}
+				resp.WriteString(formatDriveComment(c))
+				count++
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, ListDriveCommentsOutput{}, fmt.Errorf("failed to list comments: %w", err)
+	}
+
+	hidden := ""
+	if !input.IncludeResolved && resolved > 0 {
+		hidden = fmt.Sprintf(" (%d resolved comment(s) hidden; set includeResolved to show them)", resolved)
+	}
+
+	if count == 0 {
+		return nil, ListDriveCommentsOutput{Comments: "No comments found." + hidden}, nil
+	}
+
+	return nil, ListDriveCommentsOutput{Comments: fmt.Sprintf("Comments (%d)%s:\n%s", count, hidden, resp.String())}, nil
+}
+
+// CreateDriveComment handles the create_drive_comment tool call
+func CreateDriveComment(ctx context.Context, req *mcp.CallToolRequest, input CreateDriveCommentInput) (*mcp.CallToolResult, CreateDriveCommentOutput, error) {
+	if strings.TrimSpace(input.Content) == "" {
+		return nil, CreateDriveCommentOutput{}, fmt.Errorf("content is required")
+	}
+
+	srv, err := utils.NewDriveClient(input.Email)
+	if err != nil {
+		return nil, CreateDriveCommentOutput{}, err
+	}
+
+	comment := &drive.Comment{Content: input.Content}
+	if input.QuotedText != "" {
+		comment.QuotedFileContent = &drive.CommentQuotedFileContent{
+			MimeType: "text/plain",
+			Value:    input.QuotedText,
+		}
+	}
+
+	created, err := srv.Comments.Create(input.FileID, comment).
+		Fields(driveCommentFields).
+		Do()
+	if err != nil {
+		return nil, CreateDriveCommentOutput{}, fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	return nil, CreateDriveCommentOutput{Result: "Comment created successfully:\n" + formatDriveComment(created)}, nil
+}
+
+// ReplyDriveComment handles the reply_drive_comment tool call
+func ReplyDriveComment(ctx context.Context, req *mcp.CallToolRequest, input ReplyDriveCommentInput) (*mcp.CallToolResult, ReplyDriveCommentOutput, error) {
+	if strings.TrimSpace(input.Content) == "" {
+		return nil, ReplyDriveCommentOutput{}, fmt.Errorf("content is required")
+	}
+
+	srv, err := utils.NewDriveClient(input.Email)
+	if err != nil {
+		return nil, ReplyDriveCommentOutput{}, err
+	}
+
+	reply, err := srv.Replies.Create(input.FileID, input.CommentID, &drive.Reply{Content: input.Content}).
+		Fields("id, content, createdTime, author(displayName, emailAddress)").
+		Do()
+	if err != nil {
+		return nil, ReplyDriveCommentOutput{}, fmt.Errorf("failed to reply to comment: %w", err)
+	}
+
+	resp := fmt.Sprintf("Reply created successfully:\n  Reply ID: %s\n  Comment ID: %s\n  Author: %s\n  Created: %s",
+		reply.Id, input.CommentID, describeDriveUser(reply.Author), reply.CreatedTime)
+
+	return nil, ReplyDriveCommentOutput{Result: resp}, nil
+}
+
+// ResolveDriveComment handles the resolve_drive_comment tool call
+func ResolveDriveComment(ctx context.Context, req *mcp.CallToolRequest, input ResolveDriveCommentInput) (*mcp.CallToolResult, ResolveDriveCommentOutput, error) {
+	srv, err := utils.NewDriveClient(input.Email)
+	if err != nil {
+		return nil, ResolveDriveCommentOutput{}, err
+	}
+
+	// Resolving and reopening are expressed as replies carrying an action.
+	action, verb := "reopen", "reopened"
+	if input.Resolved {
+		action, verb = "resolve", "resolved"
+	}
+
+	_, err = srv.Replies.Create(input.FileID, input.CommentID, &drive.Reply{
+		Action:  action,
+		Content: input.Content,
+	}).
+		Fields("id").
+		Do()
+	if err != nil {
+		return nil, ResolveDriveCommentOutput{}, fmt.Errorf("failed to %s comment: %w", action, err)
+	}
+
+	return nil, ResolveDriveCommentOutput{Result: fmt.Sprintf("Comment %s %s on file %s", input.CommentID, verb, input.FileID)}, nil
+}
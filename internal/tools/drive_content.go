@@ -0,0 +1,121 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.orx.me/mcp/google-workspace/internal/utils"
+	"google.golang.org/api/drive/v3"
+)
+
+// UpdateDriveFileContentInput defines input for update_drive_file_content tool
+type UpdateDriveFileContentInput struct {
+	Email                  string `json:"email" jsonschema:"Email address to access Drive"`
+	FileID                 string `json:"fileId" jsonschema:"File ID whose content to replace"`
+	Content                string `json:"content,omitempty" jsonschema:"New content as text (use either content or filePath)"`
+	FilePath               string `json:"filePath,omitempty" jsonschema:"Local file inside the sandbox to upload as the new content (use either content or filePath)"`
+	MimeType               string `json:"mimeType,omitempty" jsonschema:"MIME type of the new content (default: the file's current type)"`
+	ExpectedHeadRevisionID string `json:"expectedHeadRevisionId,omitempty" jsonschema:"Head revision ID the caller last read (from get_drive_file); the update is rejected if the file has changed since"`
+	ExpectedModifiedTime   string `json:"expectedModifiedTime,omitempty" jsonschema:"Modified time the caller last read, in RFC3339 format; the update is rejected if the file has changed since"`
+	Force                  bool   `json:"force,omitempty" jsonschema:"Overwrite without checking expectedHeadRevisionId or expectedModifiedTime"`
+	KeepForever            bool   `json:"keepForever,omitempty" jsonschema:"Keep the new head revision forever"`
+}
+
+// UpdateDriveFileContentOutput defines output for update_drive_file_content tool
+type UpdateDriveFileContentOutput struct {
+	Result string `json:"result" jsonschema:"Updated file information"`
+}
+
+// checkDriveFileUnchanged reports an error when file no longer matches the
+// head revision or modified time the caller last read. Empty expectations are
+// not checked.
+func checkDriveFileUnchanged(file *drive.File, expectedHeadRevisionID, expectedModifiedTime string) error {
+	if expectedHeadRevisionID != "" && file.HeadRevisionId != expectedHeadRevisionID {
+		return fmt.Errorf("file %s has changed: head revision is %s, expected %s; re-read the file and retry", file.Name, file.HeadRevisionId, expectedHeadRevisionID)
+	}
+	if expectedModifiedTime != "" {
+		expected, err := time.Parse(time.RFC3339, expectedModifiedTime)
+		if err != nil {
+			return fmt.Errorf("invalid expectedModifiedTime %q: expected RFC3339 format", expectedModifiedTime)
+		}
+		actual, err := time.Parse(time.RFC3339, file.ModifiedTime)
+		if err != nil {
+			return fmt.Errorf("failed to parse modified time %q of file %s", file.ModifiedTime, file.Name)
+		}
+		if !actual.Equal(expected) {
+			return fmt.Errorf("file %s has changed: modified at %s, expected %s; re-read the file and retry", file.Name, file.ModifiedTime, expectedModifiedTime)
+		}
+	}
+	return nil
+}
+
+// UpdateDriveFileContent handles the update_drive_file_content tool call
+func UpdateDriveFileContent(ctx context.Context, req *mcp.CallToolRequest, input UpdateDriveFileContentInput) (*mcp.CallToolResult, UpdateDriveFileContentOutput, error) {
+	if (input.Content == "") == (input.FilePath == "") {
+		return nil, UpdateDriveFileContentOutput{}, fmt.Errorf("exactly one of content or filePath is required")
+	}
+	if !input.Force && input.ExpectedHeadRevisionID == "" && input.ExpectedModifiedTime == "" {
+		return nil, UpdateDriveFileContentOutput{}, fmt.Errorf("expectedHeadRevisionId or expectedModifiedTime is required unless force is true")
+	}
+
+	var content io.Reader
+	mimeType := input.MimeType
+	if input.FilePath != "" {
+		path, err := utils.SandboxPath(input.FilePath)
+		if err != nil {
+			return nil, UpdateDriveFileContentOutput{}, err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, UpdateDriveFileContentOutput{}, fmt.Errorf("failed to open file: %w", err)
+		}
+		defer f.Close()
+		content = f
+		if mimeType == "" {
+			mimeType = mime.TypeByExtension(filepath.Ext(path))
+		}
+	} else {
+		content = strings.NewReader(input.Content)
+	}
+
+	srv, err := utils.NewDriveClient(input.Email)
+	if err != nil {
+		return nil, UpdateDriveFileContentOutput{}, err
+	}
+
+	file, err := srv.Files.Get(input.FileID).
+		SupportsAllDrives(true).
+		Fields("id, name, mimeType, modifiedTime, headRevisionId").
+		Do()
+	if err != nil {
+		return nil, UpdateDriveFileContentOutput{}, fmt.Errorf("failed to get file info: %w", err)
+	}
+	if isGoogleNativeMimeType(file.MimeType) {
+		return nil, UpdateDriveFileContentOutput{}, fmt.Errorf("%s is a Google-native file (%s); use the Docs or Sheets tools to edit it", file.Name, file.MimeType)
+	}
+	if !input.Force {
+		if err := checkDriveFileUnchanged(file, input.ExpectedHeadRevisionID, input.ExpectedModifiedTime); err != nil {
+			return nil, UpdateDriveFileContentOutput{}, err
+		}
+	}
+	if mimeType == "" {
+		mimeType = file.MimeType
+	}
+
+	updated, err := updateDriveFileContent(ctx, srv.Files.Update(input.FileID, &drive.File{}), content, mimeType, input.KeepForever)
+	if err != nil {
+		return nil, UpdateDriveFileContentOutput{}, fmt.Errorf("failed to update file content: %w", err)
+	}
+
+	resp := fmt.Sprintf("File content updated successfully:\n  Name: %s\n  ID: %s\n  Type: %s\n  Size: %d bytes\n  Previous head revision: %s\n  New head revision: %s\n  Modified: %s\n  Link: %s",
+		updated.Name, updated.Id, updated.MimeType, updated.Size, file.HeadRevisionId, updated.HeadRevisionId, updated.ModifiedTime, updated.WebViewLink)
+
+	return nil, UpdateDriveFileContentOutput{Result: resp}, nil
+}
@@ -0,0 +1,330 @@
+package tools
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.orx.me/mcp/google-workspace/internal/utils"
+	"google.golang.org/api/drive/v3"
+)
+
+// DownloadDriveFileInput defines input for download_drive_file tool
+type DownloadDriveFileInput struct {
+	Email          string `json:"email" jsonschema:"Email address to access Drive"`
+	FileID         string `json:"fileId" jsonschema:"File or folder ID to download"`
+	Destination    string `json:"destination,omitempty" jsonschema:"Local directory inside the sandbox to save into (default: sandbox root)"`
+	Recursive      bool   `json:"recursive,omitempty" jsonschema:"Download folder contents recursively (required when fileId is a folder)"`
+	ExportMimeType string `json:"exportMimeType,omitempty" jsonschema:"MIME type to export Google Docs/Sheets/Slides as (e.g. application/pdf, text/csv); defaults to the matching Office format"`
+	Overwrite      bool   `json:"overwrite,omitempty" jsonschema:"Overwrite local files that already exist (default false)"`
+}
+
+// DownloadDriveFileOutput defines output for download_drive_file tool
+type DownloadDriveFileOutput struct {
+	Result string `json:"result" jsonschema:"Downloaded files and verification results"`
+}
+
+const driveFolderMimeType = "application/vnd.google-apps.folder"
+
+// defaultExportMimeTypes maps Google-native document types to the format they
+// are exported as when the caller does not ask for a specific one.
+var defaultExportMimeTypes = map[string]string{
+	"application/vnd.google-apps.document":     "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	"application/vnd.google-apps.spreadsheet":  "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	"application/vnd.google-apps.presentation": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	"application/vnd.google-apps.drawing":      "image/png",
+	"application/vnd.google-apps.script":       "application/vnd.google-apps.script+json",
+}
+
+// exportExtensions maps export MIME types to the file extension appended to
+// the exported file's name.
+var exportExtensions = map[string]string{
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document":   ".docx",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":         ".xlsx",
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation": ".pptx",
+	"application/vnd.oasis.opendocument.text":                                   ".odt",
+	"application/vnd.oasis.opendocument.spreadsheet":                            ".ods",
+	"application/vnd.oasis.opendocument.presentation":                           ".odp",
+	"application/vnd.google-apps.script+json":                                   ".json",
+	"application/pdf":           ".pdf",
+	"application/rtf":           ".rtf",
+	"application/zip":           ".zip",
+	"application/epub+zip":      ".epub",
+	"text/plain":                ".txt",
+	"text/csv":                  ".csv",
+	"text/tab-separated-values": ".tsv",
+	"text/html":                 ".html",
+	"text/markdown":             ".md",
+	"image/png":                 ".png",
+	"image/jpeg":                ".jpg",
+	"image/svg+xml":             ".svg",
+}
+
+// isGoogleNativeMimeType reports whether the file has no binary content of its
+// own and must be exported instead of downloaded.
+func isGoogleNativeMimeType(mimeType string) bool {
+	return strings.HasPrefix(mimeType, "application/vnd.google-apps.")
+}
+
+// localFileName turns a Drive file name into something safe to use as a single
+// path component, falling back to the file ID when nothing usable is left.
+func localFileName(name, fileID string) string {
+	name = strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', 0:
+			return '_'
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == ".." {
+		return fileID
+	}
+	return name
+}
+
+// driveDownloader carries the state of a single download_drive_file call
+// while it walks a file or folder tree.
+type driveDownloader struct {
+	ctx        context.Context
+	srv        *drive.Service
+	exportMime string
+	overwrite  bool
+	recursive  bool
+
+	report  strings.Builder
+	saved   int
+	skipped int
+	bytes   int64
+	written map[string]bool
+}
+
+// download saves file into dir, descending into folders when recursive.
+func (d *driveDownloader) download(file *drive.File, dir string) error {
+	if file.MimeType == driveFolderMimeType {
+		if !d.recursive {
+			return fmt.Errorf("%s is a folder; set recursive to download its contents", file.Name)
+		}
+		return d.downloadFolder(file, dir)
+	}
+
+	if isGoogleNativeMimeType(file.MimeType) {
+		return d.export(file, dir)
+	}
+
+	target := d.targetPath(dir, localFileName(file.Name, file.Id), file.Id)
+	if d.skipExisting(target) {
+		return nil
+	}
+
+	resp, err := d.srv.Files.Get(file.Id).SupportsAllDrives(true).Context(d.ctx).Download()
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", file.Name, err)
+	}
+	defer resp.Body.Close()
+
+	size, sum, err := writeLocalFile(target, resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to save %s: %w", file.Name, err)
+	}
+
+	if file.Size > 0 && size != file.Size {
+		os.Remove(target)
+		return fmt.Errorf("size mismatch for %s: expected %d bytes, wrote %d", file.Name, file.Size, size)
+	}
+	if file.Md5Checksum != "" && sum != file.Md5Checksum {
+		os.Remove(target)
+		return fmt.Errorf("md5 mismatch for %s: expected %s, got %s", file.Name, file.Md5Checksum, sum)
+	}
+
+	verified := "size and md5 verified"
+	if file.Md5Checksum == "" {
+		verified = "no checksum available"
+	}
+	d.saved++
+	d.bytes += size
+	d.report.WriteString(fmt.Sprintf("Saved: %s (%d bytes, %s)\n", target, size, verified))
+	return nil
+}
+
+// export saves a Google-native file in the requested or default export format.
+func (d *driveDownloader) export(file *drive.File, dir string) error {
+	exportMime := d.exportMime
+	if exportMime == "" {
+		exportMime = defaultExportMimeTypes[file.MimeType]
+	}
+	if exportMime == "" {
+		d.skipped++
+		d.report.WriteString(fmt.Sprintf("Skipped: %s (%s cannot be exported)\n", file.Name, file.MimeType))
+		return nil
+	}
+
+	name := localFileName(file.Name, file.Id)
+	if ext := exportExtensions[exportMime]; ext != "" && !strings.HasSuffix(strings.ToLower(name), ext) {
+		name += ext
+	}
+	target := d.targetPath(dir, name, file.Id)
+	if d.skipExisting(target) {
+		return nil
+	}
+
+	resp, err := d.srv.Files.Export(file.Id, exportMime).Context(d.ctx).Download()
+	if err != nil {
+		return fmt.Errorf("failed to export %s as %s: %w", file.Name, exportMime, err)
+	}
+	defer resp.Body.Close()
+
+	size, _, err := writeLocalFile(target, resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to save %s: %w", file.Name, err)
+	}
+
+	d.saved++
+	d.bytes += size
+	d.report.WriteString(fmt.Sprintf("Exported: %s (%d bytes, %s)\n", target, size, exportMime))
+	return nil
+}
+
+// downloadFolder recreates folder under dir and downloads its children into it.
+func (d *driveDownloader) downloadFolder(folder *drive.File, dir string) error {
+	local := d.targetPath(dir, localFileName(folder.Name, folder.Id), folder.Id)
+	if _, err := utils.SandboxPath(local); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(local, 0o755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", local, err)
+	}
+	d.report.WriteString(fmt.Sprintf("Folder: %s\n", local))
+
+	query := (&driveQuery{}).InParents(folder.Id).Trashed(false)
+	return d.srv.Files.List().
+		SupportsAllDrives(true).
+		IncludeItemsFromAllDrives(true).
+		Q(query.String()).
+		PageSize(100).
+		Fields("nextPageToken, files(id, name, mimeType, size, md5Checksum)").
+		Pages(d.ctx, func(page *drive.FileList) error {
+			for _, child := range page.Files {
+				if err := d.download(child, local); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+}
+
+// targetPath joins name onto dir, disambiguating with the file ID when Drive
+// holds several items with the same name in one folder.
+func (d *driveDownloader) targetPath(dir, name, fileID string) string {
+	return uniqueLocalPath(d.written, dir, name, fileID)
+}
+
+// uniqueLocalPath joins name onto dir. When an earlier item of the same call
+// already took that path, id is added before the extension instead. written
+// records the paths handed out.
+func uniqueLocalPath(written map[string]bool, dir, name, id string) string {
+	target := filepath.Join(dir, name)
+	if written[target] {
+		ext := filepath.Ext(name)
+		target = filepath.Join(dir, fmt.Sprintf("%s (%s)%s", strings.TrimSuffix(name, ext), id, ext))
+	}
+	written[target] = true
+	return target
+}
+
+// skipExisting reports whether target already exists and must be left alone.
+func (d *driveDownloader) skipExisting(target string) bool {
+	if d.overwrite {
+		return false
+	}
+	if _, err := os.Stat(target); err != nil {
+		return false
+	}
+	d.skipped++
+	d.report.WriteString(fmt.Sprintf("Skipped: %s (already exists)\n", target))
+	return true
+}
+
+// writeLocalFile streams r into target through a temporary file in the same
+// directory, returning the number of bytes written and their MD5 checksum.
+// target is only replaced once the whole body has been written. Targets are
+// built from remote names, so each one is checked against the sandbox again:
+// a symlink inside it must not redirect the write elsewhere.
+func writeLocalFile(target string, r io.Reader) (int64, string, error) {
+	if _, err := utils.SandboxPath(target); err != nil {
+		return 0, "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return 0, "", err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".download-*")
+	if err != nil {
+		return 0, "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := md5.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), r)
+	if err != nil {
+		tmp.Close()
+		return 0, "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, "", err
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return 0, "", err
+	}
+
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// DownloadDriveFile handles the download_drive_file tool call
+func DownloadDriveFile(ctx context.Context, req *mcp.CallToolRequest, input DownloadDriveFileInput) (*mcp.CallToolResult, DownloadDriveFileOutput, error) {
+	destination := input.Destination
+	if destination == "" {
+		destination = "."
+	}
+	dir, err := utils.SandboxPath(destination)
+	if err != nil {
+		return nil, DownloadDriveFileOutput{}, err
+	}
+
+	srv, err := utils.NewDriveClient(input.Email)
+	if err != nil {
+		return nil, DownloadDriveFileOutput{}, err
+	}
+
+	file, err := srv.Files.Get(input.FileID).
+		SupportsAllDrives(true).
+		Fields("id, name, mimeType, size, md5Checksum").
+		Do()
+	if err != nil {
+		return nil, DownloadDriveFileOutput{}, fmt.Errorf("failed to get file info: %w", err)
+	}
+
+	d := &driveDownloader{
+		ctx:        ctx,
+		srv:        srv,
+		exportMime: input.ExportMimeType,
+		overwrite:  input.Overwrite,
+		recursive:  input.Recursive,
+		written:    map[string]bool{},
+	}
+	if err := d.download(file, dir); err != nil {
+		return nil, DownloadDriveFileOutput{}, err
+	}
+
+	var resp strings.Builder
+	resp.WriteString(fmt.Sprintf("Download complete: %d file(s), %d bytes, %d skipped\n\n", d.saved, d.bytes, d.skipped))
+	resp.WriteString(d.report.String())
+
+	return nil, DownloadDriveFileOutput{Result: resp.String()}, nil
+}
@@ -0,0 +1,268 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.orx.me/mcp/google-workspace/internal/utils"
+	"google.golang.org/api/drive/v3"
+)
+
+// RenameDriveFileInput defines input for rename_drive_file tool
+type RenameDriveFileInput struct {
+	Email  string `json:"email" jsonschema:"Email address to access Drive"`
+	FileID string `json:"fileId" jsonschema:"File or folder ID to rename"`
+	Name   string `json:"name" jsonschema:"New name"`
+}
+
+// RenameDriveFileOutput defines output for rename_drive_file tool
+type RenameDriveFileOutput struct {
+	Result string `json:"result" jsonschema:"Rename result"`
+}
+
+// MoveDriveFileInput defines input for move_drive_file tool
+type MoveDriveFileInput struct {
+	Email         string `json:"email" jsonschema:"Email address to access Drive"`
+	FileID        string `json:"fileId" jsonschema:"File or folder ID to move"`
+	DestinationID string `json:"destinationId" jsonschema:"ID of the destination folder or shared drive"`
+}
+
+// MoveDriveFileOutput defines output for move_drive_file tool
+type MoveDriveFileOutput struct {
+	Result string `json:"result" jsonschema:"Move result"`
+}
+
+// CopyDriveFileInput defines input for copy_drive_file tool
+type CopyDriveFileInput struct {
+	Email    string `json:"email" jsonschema:"Email address to access Drive"`
+	FileID   string `json:"fileId" jsonschema:"File ID to copy (Google Docs, Sheets and Slides are supported)"`
+	Name     string `json:"name,omitempty" jsonschema:"Name for the copy (default: 'Copy of' the original name)"`
+	ParentID string `json:"parentId,omitempty" jsonschema:"Folder or shared drive ID to place the copy in (default: same folder as the original)"`
+}
+
+// CopyDriveFileOutput defines output for copy_drive_file tool
+type CopyDriveFileOutput struct {
+	Result string `json:"result" jsonschema:"Copied file information"`
+}
+
+// TrashDriveFileInput defines input for trash_drive_file tool
+type TrashDriveFileInput struct {
+	Email  string `json:"email" jsonschema:"Email address to access Drive"`
+	FileID string `json:"fileId" jsonschema:"File or folder ID to move to the trash"`
+}
+
+// TrashDriveFileOutput defines output for trash_drive_file tool
+type TrashDriveFileOutput struct {
+	Result string `json:"result" jsonschema:"Trash result"`
+}
+
+// RestoreDriveFileInput defines input for restore_drive_file tool
+type RestoreDriveFileInput struct {
+	Email  string `json:"email" jsonschema:"Email address to access Drive"`
+	FileID string `json:"fileId" jsonschema:"File or folder ID to restore from the trash"`
+}
+
+// RestoreDriveFileOutput defines output for restore_drive_file tool
+type RestoreDriveFileOutput struct {
+	Result string `json:"result" jsonschema:"Restore result"`
+}
+
+// DeleteDriveFileInput defines input for delete_drive_file tool
+type DeleteDriveFileInput struct {
+	Email   string `json:"email" jsonschema:"Email address to access Drive"`
+	FileID  string `json:"fileId" jsonschema:"File or folder ID to delete permanently"`
+	Confirm bool   `json:"confirm" jsonschema:"Must be true; the file skips the trash and cannot be recovered"`
+}
+
+// DeleteDriveFileOutput defines output for delete_drive_file tool
+type DeleteDriveFileOutput struct {
+	Result string `json:"result" jsonschema:"Delete result"`
+}
+
+// EmptyDriveTrashInput defines input for empty_drive_trash tool
+type EmptyDriveTrashInput struct {
+	Email   string `json:"email" jsonschema:"Email address to access Drive"`
+	DriveID string `json:"driveId,omitempty" jsonschema:"Shared drive ID whose trash to empty (default: the user's My Drive trash)"`
+	Confirm bool   `json:"confirm" jsonschema:"Must be true; every trashed file is deleted permanently"`
+}
+
+// EmptyDriveTrashOutput defines output for empty_drive_trash tool
+type EmptyDriveTrashOutput struct {
+	Result string `json:"result" jsonschema:"Empty trash result"`
+}
+
+// boolPtr returns a pointer to b, for optional fields such as tool annotation hints.
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+// RenameDriveFile handles the rename_drive_file tool call
+func RenameDriveFile(ctx context.Context, req *mcp.CallToolRequest, input RenameDriveFileInput) (*mcp.CallToolResult, RenameDriveFileOutput, error) {
+	srv, err := utils.NewDriveClient(input.Email)
+	if err != nil {
+		return nil, RenameDriveFileOutput{}, err
+	}
+
+	file, err := srv.Files.Update(input.FileID, &drive.File{Name: input.Name}).
+		SupportsAllDrives(true).
+		Fields("id, name, webViewLink").
+		Do()
+	if err != nil {
+		return nil, RenameDriveFileOutput{}, fmt.Errorf("failed to rename file: %w", err)
+	}
+
+	resp := fmt.Sprintf("File renamed successfully:\n  Name: %s\n  ID: %s\n  Link: %s", file.Name, file.Id, file.WebViewLink)
+
+	return nil, RenameDriveFileOutput{Result: resp}, nil
+}
+
+// MoveDriveFile handles the move_drive_file tool call
+func MoveDriveFile(ctx context.Context, req *mcp.CallToolRequest, input MoveDriveFileInput) (*mcp.CallToolResult, MoveDriveFileOutput, error) {
+	srv, err := utils.NewDriveClient(input.Email)
+	if err != nil {
+		return nil, MoveDriveFileOutput{}, err
+	}
+
+	destination, err := srv.Files.Get(input.DestinationID).
+		SupportsAllDrives(true).
+		Fields("id, name, mimeType").
+		Do()
+	if err != nil {
+		return nil, MoveDriveFileOutput{}, fmt.Errorf("failed to get destination: %w", err)
+	}
+	if destination.MimeType != driveFolderMimeType {
+		return nil, MoveDriveFileOutput{}, fmt.Errorf("destination %s (%s) is not a folder", destination.Name, destination.MimeType)
+	}
+
+	file, err := srv.Files.Get(input.FileID).
+		SupportsAllDrives(true).
+		Fields("parents").
+		Do()
+	if err != nil {
+		return nil, MoveDriveFileOutput{}, fmt.Errorf("failed to get file info: %w", err)
+	}
+
+	moved, err := srv.Files.Update(input.FileID, &drive.File{}).
+		AddParents(destination.Id).
+		RemoveParents(strings.Join(file.Parents, ",")).
+		SupportsAllDrives(true).
+		Fields("id, name, webViewLink").
+		Do()
+	if err != nil {
+		return nil, MoveDriveFileOutput{}, fmt.Errorf("failed to move file: %w", err)
+	}
+
+	resp := fmt.Sprintf("File moved successfully:\n  Name: %s\n  ID: %s\n  Destination: %s (%s)\n  Link: %s",
+		moved.Name, moved.Id, destination.Name, destination.Id, moved.WebViewLink)
+
+	return nil, MoveDriveFileOutput{Result: resp}, nil
+}
+
+// CopyDriveFile handles the copy_drive_file tool call
+func CopyDriveFile(ctx context.Context, req *mcp.CallToolRequest, input CopyDriveFileInput) (*mcp.CallToolResult, CopyDriveFileOutput, error) {
+	srv, err := utils.NewDriveClient(input.Email)
+	if err != nil {
+		return nil, CopyDriveFileOutput{}, err
+	}
+
+	fileMetadata := &drive.File{
+		Name: input.Name,
+	}
+	if input.ParentID != "" {
+		fileMetadata.Parents = []string{input.ParentID}
+	}
+
+	copied, err := srv.Files.Copy(input.FileID, fileMetadata).
+		SupportsAllDrives(true).
+		Fields("id, name, mimeType, webViewLink").
+		Do()
+	if err != nil {
+		return nil, CopyDriveFileOutput{}, fmt.Errorf("failed to copy file: %w", err)
+	}
+
+	resp := fmt.Sprintf("File copied successfully:\n  Name: %s\n  ID: %s\n  Type: %s\n  Link: %s",
+		copied.Name, copied.Id, copied.MimeType, copied.WebViewLink)
+
+	return nil, CopyDriveFileOutput{Result: resp}, nil
+}
+
+// TrashDriveFile handles the trash_drive_file tool call
+func TrashDriveFile(ctx context.Context, req *mcp.CallToolRequest, input TrashDriveFileInput) (*mcp.CallToolResult, TrashDriveFileOutput, error) {
+	file, err := setDriveFileTrashed(input.Email, input.FileID, true)
+	if err != nil {
+		return nil, TrashDriveFileOutput{}, fmt.Errorf("failed to trash file: %w", err)
+	}
+
+	return nil, TrashDriveFileOutput{Result: fmt.Sprintf("File %s (%s) moved to the trash", file.Name, file.Id)}, nil
+}
+
+// RestoreDriveFile handles the restore_drive_file tool call
+func RestoreDriveFile(ctx context.Context, req *mcp.CallToolRequest, input RestoreDriveFileInput) (*mcp.CallToolResult, RestoreDriveFileOutput, error) {
+	file, err := setDriveFileTrashed(input.Email, input.FileID, false)
+	if err != nil {
+		return nil, RestoreDriveFileOutput{}, fmt.Errorf("failed to restore file: %w", err)
+	}
+
+	return nil, RestoreDriveFileOutput{Result: fmt.Sprintf("File %s (%s) restored from the trash", file.Name, file.Id)}, nil
+}
+
+// setDriveFileTrashed moves a file into or out of the trash.
+func setDriveFileTrashed(email, fileID string, trashed bool) (*drive.File, error) {
+	srv, err := utils.NewDriveClient(email)
+	if err != nil {
+		return nil, err
+	}
+
+	return srv.Files.Update(fileID, &drive.File{
+		Trashed:         trashed,
+		ForceSendFields: []string{"Trashed"},
+	}).
+		SupportsAllDrives(true).
+		Fields("id, name").
+		Do()
+}
+
+// DeleteDriveFile handles the delete_drive_file tool call
+func DeleteDriveFile(ctx context.Context, req *mcp.CallToolRequest, input DeleteDriveFileInput) (*mcp.CallToolResult, DeleteDriveFileOutput, error) {
+	if !input.Confirm {
+		return nil, DeleteDriveFileOutput{}, fmt.Errorf("permanent deletion requires confirm to be true; use trash_drive_file for a recoverable delete")
+	}
+
+	srv, err := utils.NewDriveClient(input.Email)
+	if err != nil {
+		return nil, DeleteDriveFileOutput{}, err
+	}
+
+	if err := srv.Files.Delete(input.FileID).SupportsAllDrives(true).Do(); err != nil {
+		return nil, DeleteDriveFileOutput{}, fmt.Errorf("failed to delete file: %w", err)
+	}
+
+	return nil, DeleteDriveFileOutput{Result: fmt.Sprintf("File %s deleted permanently", input.FileID)}, nil
+}
+
+// EmptyDriveTrash handles the empty_drive_trash tool call
+func EmptyDriveTrash(ctx context.Context, req *mcp.CallToolRequest, input EmptyDriveTrashInput) (*mcp.CallToolResult, EmptyDriveTrashOutput, error) {
+	if !input.Confirm {
+		return nil, EmptyDriveTrashOutput{}, fmt.Errorf("emptying the trash requires confirm to be true")
+	}
+
+	srv, err := utils.NewDriveClient(input.Email)
+	if err != nil {
+		return nil, EmptyDriveTrashOutput{}, err
+	}
+
+	call := srv.Files.EmptyTrash()
+	if input.DriveID != "" {
+		call = call.DriveId(input.DriveID)
+	}
+	if err := call.Do(); err != nil {
+		return nil, EmptyDriveTrashOutput{}, fmt.Errorf("failed to empty trash: %w", err)
+	}
+
+	if input.DriveID != "" {
+		return nil, EmptyDriveTrashOutput{Result: fmt.Sprintf("Trash emptied for shared drive %s", input.DriveID)}, nil
+	}
+	return nil, EmptyDriveTrashOutput{Result: fmt.Sprintf("Trash emptied for %s", input.Email)}, nil
+}
@@ -0,0 +1,192 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.orx.me/mcp/google-workspace/internal/utils"
+	"google.golang.org/api/drive/v3"
+)
+
+// CreateDriveShortcutInput defines input for create_drive_shortcut tool
+type CreateDriveShortcutInput struct {
+	Email    string `json:"email" jsonschema:"Email address to access Drive"`
+	TargetID string `json:"targetId" jsonschema:"ID of the file or folder the shortcut points to"`
+	ParentID string `json:"parentId,omitempty" jsonschema:"Folder or shared drive ID to place the shortcut in (default: My Drive root)"`
+	Name     string `json:"name,omitempty" jsonschema:"Name of the shortcut (default: the target's name)"`
+}
+
+// CreateDriveShortcutOutput defines output for create_drive_shortcut tool
+type CreateDriveShortcutOutput struct {
+	Result string `json:"result" jsonschema:"Created shortcut information"`
+}
+
+// SetDriveFileMetadataInput defines input for set_drive_file_metadata tool
+type SetDriveFileMetadataInput struct {
+	Email               string            `json:"email" jsonschema:"Email address to access Drive"`
+	FileID              string            `json:"fileId" jsonschema:"File or folder ID to update"`
+	Starred             *bool             `json:"starred,omitempty" jsonschema:"Star (true) or unstar (false) the file"`
+	Description         *string           `json:"description,omitempty" jsonschema:"New description; an empty string clears it"`
+	FolderColorRgb      string            `json:"folderColorRgb,omitempty" jsonschema:"Folder color as #RRGGBB; Drive picks the closest color from its palette"`
+	AppProperties       map[string]string `json:"appProperties,omitempty" jsonschema:"Custom app properties to set or overwrite"`
+	RemoveAppProperties []string          `json:"removeAppProperties,omitempty" jsonschema:"Keys of custom app properties to remove"`
+}
+
+// SetDriveFileMetadataOutput defines output for set_drive_file_metadata tool
+type SetDriveFileMetadataOutput struct {
+	Result string `json:"result" jsonschema:"Updated file metadata"`
+}
+
+const driveShortcutMimeType = "application/vnd.google-apps.shortcut"
+
+// folderColorPattern matches a #RRGGBB color.
+var folderColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// formatDriveListEntry renders a file for list_drive_files and
+// search_drive_files. Shortcuts are shown with the type and ID of their target
+// so they can be used like the file they point to.
+func formatDriveListEntry(file *drive.File) string {
+	mimeType := file.MimeType
+	shortcut := ""
+	if file.MimeType == driveShortcutMimeType && file.ShortcutDetails != nil {
+		mimeType = file.ShortcutDetails.TargetMimeType
+		shortcut = fmt.Sprintf("  Shortcut to: %s\n", file.ShortcutDetails.TargetId)
+	}
+
+	fileType := "File"
+	if mimeType == driveFolderMimeType {
+		fileType = "Folder"
+	}
+	return fmt.Sprintf("[%s] %s\n  ID: %s\n%s  Type: %s\n  Modified: %s\n  Link: %s\n\n",
+		fileType, file.Name, file.Id, shortcut, mimeType, file.ModifiedTime, file.WebViewLink)
+}
+
+// newDriveFileMetadataUpdate validates the requested changes and returns the
+// metadata to send to Files.Update.
+func newDriveFileMetadataUpdate(input SetDriveFileMetadataInput) (*drive.File, error) {
+	update := &drive.File{}
+	changed := false
+
+	if input.Starred != nil {
+		update.Starred = *input.Starred
+		update.ForceSendFields = append(update.ForceSendFields, "Starred")
+		changed = true
+	}
+	if input.Description != nil {
+		update.Description = *input.Description
+		update.ForceSendFields = append(update.ForceSendFields, "Description")
+		changed = true
+	}
+	if input.FolderColorRgb != "" {
+		if !folderColorPattern.MatchString(input.FolderColorRgb) {
+			return nil, fmt.Errorf("invalid folderColorRgb %q: expected #RRGGBB", input.FolderColorRgb)
+		}
+		update.FolderColorRgb = strings.ToLower(input.FolderColorRgb)
+		changed = true
+	}
+	if len(input.AppProperties) > 0 {
+		update.AppProperties = input.AppProperties
+		changed = true
+	}
+	for _, key := range input.RemoveAppProperties {
+		if _, ok := input.AppProperties[key]; ok {
+			return nil, fmt.Errorf("app property %q cannot be both set and removed", key)
+		}
+		update.NullFields = append(update.NullFields, "AppProperties."+key)
+		changed = true
+	}
+
+	if !changed {
+		return nil, fmt.Errorf("nothing to update: set starred, description, folderColorRgb, appProperties or removeAppProperties")
+	}
+	return update, nil
+}
+
+// CreateDriveShortcut handles the create_drive_shortcut tool call
+func CreateDriveShortcut(ctx context.Context, req *mcp.CallToolRequest, input CreateDriveShortcutInput) (*mcp.CallToolResult, CreateDriveShortcutOutput, error) {
+	srv, err := utils.NewDriveClient(input.Email)
+	if err != nil {
+		return nil, CreateDriveShortcutOutput{}, err
+	}
+
+	name := input.Name
+	if name == "" {
+		target, err := srv.Files.Get(input.TargetID).
+			SupportsAllDrives(true).
+			Fields("name").
+			Do()
+		if err != nil {
+			return nil, CreateDriveShortcutOutput{}, fmt.Errorf("failed to get shortcut target: %w", err)
+		}
+		name = target.Name
+	}
+
+	shortcut := &drive.File{
+		Name:            name,
+		MimeType:        driveShortcutMimeType,
+		ShortcutDetails: &drive.FileShortcutDetails{TargetId: input.TargetID},
+	}
+	if input.ParentID != "" {
+		shortcut.Parents = []string{input.ParentID}
+	}
+
+	created, err := srv.Files.Create(shortcut).
+		SupportsAllDrives(true).
+		Fields("id, name, webViewLink, shortcutDetails").
+		Do()
+	if err != nil {
+		return nil, CreateDriveShortcutOutput{}, fmt.Errorf("failed to create shortcut: %w", err)
+	}
+
+	resp := fmt.Sprintf("Shortcut created successfully:\n  Name: %s\n  ID: %s\n  Target: %s (%s)\n  Link: %s",
+		created.Name, created.Id, created.ShortcutDetails.TargetId, created.ShortcutDetails.TargetMimeType, created.WebViewLink)
+
+	return nil, CreateDriveShortcutOutput{Result: resp}, nil
+}
+
+// SetDriveFileMetadata handles the set_drive_file_metadata tool call
+func SetDriveFileMetadata(ctx context.Context, req *mcp.CallToolRequest, input SetDriveFileMetadataInput) (*mcp.CallToolResult, SetDriveFileMetadataOutput, error) {
+	update, err := newDriveFileMetadataUpdate(input)
+	if err != nil {
+		return nil, SetDriveFileMetadataOutput{}, err
+	}
+
+	srv, err := utils.NewDriveClient(input.Email)
+	if err != nil {
+		return nil, SetDriveFileMetadataOutput{}, err
+	}
+
+	file, err := srv.Files.Update(input.FileID, update).
+		SupportsAllDrives(true).
+		Fields("id, name, mimeType, starred, description, folderColorRgb, appProperties").
+		Do()
+	if err != nil {
+		return nil, SetDriveFileMetadataOutput{}, fmt.Errorf("failed to update file metadata: %w", err)
+	}
+
+	var resp strings.Builder
+	resp.WriteString(fmt.Sprintf("Metadata updated successfully:\n  Name: %s\n  ID: %s\n  Starred: %t\n", file.Name, file.Id, file.Starred))
+	if file.Description != "" {
+		resp.WriteString(fmt.Sprintf("  Description: %s\n", file.Description))
+	}
+	if file.MimeType == driveFolderMimeType && file.FolderColorRgb != "" {
+		resp.WriteString(fmt.Sprintf("  Folder color: %s\n", file.FolderColorRgb))
+	}
+	if len(file.AppProperties) > 0 {
+		keys := make([]string, 0, len(file.AppProperties))
+		for k := range file.AppProperties {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		resp.WriteString("  App properties:\n")
+		for _, k := range keys {
+			resp.WriteString(fmt.Sprintf("    %s: %s\n", k, file.AppProperties[k]))
+		}
+	}
+
+	return nil, SetDriveFileMetadataOutput{Result: resp.String()}, nil
+}
@@ -0,0 +1,241 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.orx.me/mcp/google-workspace/internal/utils"
+	"google.golang.org/api/drive/v3"
+)
+
+// ListDrivePermissionsInput defines input for list_drive_permissions tool
+type ListDrivePermissionsInput struct {
+	Email  string `json:"email" jsonschema:"Email address to access Drive"`
+	FileID string `json:"fileId" jsonschema:"File or folder ID"`
+}
+
+// ListDrivePermissionsOutput defines output for list_drive_permissions tool
+type ListDrivePermissionsOutput struct {
+	Permissions string `json:"permissions" jsonschema:"List of permissions on the file"`
+}
+
+// UpdateDrivePermissionInput defines input for update_drive_permission tool
+type UpdateDrivePermissionInput struct {
+	Email             string `json:"email" jsonschema:"Email address to access Drive"`
+	FileID            string `json:"fileId" jsonschema:"File or folder ID"`
+	PermissionID      string `json:"permissionId" jsonschema:"Permission ID (from list_drive_permissions)"`
+	Role              string `json:"role,omitempty" jsonschema:"New role: reader, commenter, writer, fileOrganizer, organizer, owner"`
+	ExpirationTime    string `json:"expirationTime,omitempty" jsonschema:"New expiration time in RFC3339 format"`
+	RemoveExpiration  bool   `json:"removeExpiration,omitempty" jsonschema:"Remove the permission's expiration time"`
+	TransferOwnership bool   `json:"transferOwnership,omitempty" jsonschema:"Must be true when changing the role to owner"`
+}
+
+// UpdateDrivePermissionOutput defines output for update_drive_permission tool
+type UpdateDrivePermissionOutput struct {
+	Result string `json:"result" jsonschema:"Updated permission information"`
+}
+
+// DeleteDrivePermissionInput defines input for delete_drive_permission tool
+type DeleteDrivePermissionInput struct {
+	Email        string `json:"email" jsonschema:"Email address to access Drive"`
+	FileID       string `json:"fileId" jsonschema:"File or folder ID"`
+	PermissionID string `json:"permissionId" jsonschema:"Permission ID to remove (from list_drive_permissions)"`
+}
+
+// DeleteDrivePermissionOutput defines output for delete_drive_permission tool
+type DeleteDrivePermissionOutput struct {
+	Result string `json:"result" jsonschema:"Delete result"`
+}
+
+// drivePermissionRoles lists the roles a Drive permission can carry.
+var drivePermissionRoles = map[string]bool{
+	"reader":        true,
+	"commenter":     true,
+	"writer":        true,
+	"fileOrganizer": true,
+	"organizer":     true,
+	"owner":         true,
+}
+
+// newDrivePermission validates the sharing options and returns the permission
+// to create. email is used for user and group grants, domain for domain grants;
+// anyone grants share by link.
+func newDrivePermission(permType, role, email, domain string, allowFileDiscovery bool, expirationTime string) (*drive.Permission, error) {
+	if !drivePermissionRoles[role] {
+		return nil, fmt.Errorf("invalid role: %s (must be reader, commenter, writer, fileOrganizer, organizer, or owner)", role)
+	}
+
+	permission := &drive.Permission{
+		Type: permType,
+		Role: role,
+	}
+
+	switch permType {
+	case "user", "group":
+		if email == "" {
+			return nil, fmt.Errorf("an email address is required to share with a %s", permType)
+		}
+		permission.EmailAddress = email
+	case "domain":
+		if domain == "" {
+			return nil, fmt.Errorf("a domain is required to share with a domain")
+		}
+		permission.Domain = domain
+	case "anyone":
+	default:
+		return nil, fmt.Errorf("invalid type: %s (must be user, group, domain, or anyone)", permType)
+	}
+
+	if role == "owner" && permType != "user" {
+		return nil, fmt.Errorf("ownership can only be transferred to a user")
+	}
+
+	if allowFileDiscovery {
+		if permType != "domain" && permType != "anyone" {
+			return nil, fmt.Errorf("allowFileDiscovery only applies to domain and anyone permissions")
+		}
+		permission.AllowFileDiscovery = true
+	}
+
+	if expirationTime != "" {
+		if permType != "user" && permType != "group" {
+			return nil, fmt.Errorf("expiration times can only be set on user and group permissions")
+		}
+		if role == "owner" {
+			return nil, fmt.Errorf("expiration times cannot be set on owner permissions")
+		}
+		t, err := time.Parse(time.RFC3339, expirationTime)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expirationTime %q: expected RFC3339 format", expirationTime)
+		}
+		permission.ExpirationTime = t.UTC().Format(time.RFC3339)
+	}
+
+	return permission, nil
+}
+
+// describeDrivePermission returns who a permission grants access to.
+func describeDrivePermission(p *drive.Permission) string {
+	switch p.Type {
+	case "anyone":
+		return "Anyone with the link"
+	case "domain":
+		return "Anyone at " + p.Domain
+	}
+	if p.EmailAddress != "" {
+		return p.EmailAddress
+	}
+	return p.DisplayName
+}
+
+// ListDrivePermissions handles the list_drive_permissions tool call
+func ListDrivePermissions(ctx context.Context, req *mcp.CallToolRequest, input ListDrivePermissionsInput) (*mcp.CallToolResult, ListDrivePermissionsOutput, error) {
+	srv, err := utils.NewDriveClient(input.Email)
+	if err != nil {
+		return nil, ListDrivePermissionsOutput{}, err
+	}
+
+	var resp strings.Builder
+	count := 0
+	err = srv.Permissions.List(input.FileID).
+		SupportsAllDrives(true).
+		Fields("nextPageToken, permissions(id, type, role, emailAddress, domain, displayName, allowFileDiscovery, expirationTime, pendingOwner, permissionDetails)").
+		Pages(ctx, func(page *drive.PermissionList) error {
+			for _, p := range page.Permissions {
+				resp.WriteString(fmt.Sprintf("- %s (%s)\n  Role: %s\n  Permission ID: %s\n", describeDrivePermission(p), p.Type, p.Role, p.Id))
+				if p.AllowFileDiscovery {
+					resp.WriteString("  Discoverable: true\n")
+				}
+				if p.ExpirationTime != "" {
+					resp.WriteString(fmt.Sprintf("  Expires: %s\n", p.ExpirationTime))
+				}
+				if p.PendingOwner {
+					resp.WriteString("  Pending owner: true\n")
+				}
+				for _, d := range p.PermissionDetails {
+					if d.Inherited {
+						resp.WriteString(fmt.Sprintf("  Inherited from: %s\n", d.InheritedFrom))
+						break
+					}
+				}
+				count++
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, ListDrivePermissionsOutput{}, fmt.Errorf("failed to list permissions: %w", err)
+	}
+
+	if count == 0 {
+		return nil, ListDrivePermissionsOutput{Permissions: "No permissions found."}, nil
+	}
+
+	return nil, ListDrivePermissionsOutput{Permissions: fmt.Sprintf("Permissions (%d):\n%s", count, resp.String())}, nil
+}
+
+// UpdateDrivePermission handles the update_drive_permission tool call
+func UpdateDrivePermission(ctx context.Context, req *mcp.CallToolRequest, input UpdateDrivePermissionInput) (*mcp.CallToolResult, UpdateDrivePermissionOutput, error) {
+	permission := &drive.Permission{}
+	if input.Role != "" {
+		if !drivePermissionRoles[input.Role] {
+			return nil, UpdateDrivePermissionOutput{}, fmt.Errorf("invalid role: %s (must be reader, commenter, writer, fileOrganizer, organizer, or owner)", input.Role)
+		}
+		if input.Role == "owner" && !input.TransferOwnership {
+			return nil, UpdateDrivePermissionOutput{}, fmt.Errorf("changing the role to owner requires transferOwnership to be true")
+		}
+		permission.Role = input.Role
+	}
+	if input.ExpirationTime != "" {
+		if input.RemoveExpiration {
+			return nil, UpdateDrivePermissionOutput{}, fmt.Errorf("expirationTime and removeExpiration cannot be used together")
+		}
+		t, err := time.Parse(time.RFC3339, input.ExpirationTime)
+		if err != nil {
+			return nil, UpdateDrivePermissionOutput{}, fmt.Errorf("invalid expirationTime %q: expected RFC3339 format", input.ExpirationTime)
+		}
+		permission.ExpirationTime = t.UTC().Format(time.RFC3339)
+	}
+
+	srv, err := utils.NewDriveClient(input.Email)
+	if err != nil {
+		return nil, UpdateDrivePermissionOutput{}, err
+	}
+
+	updated, err := srv.Permissions.Update(input.FileID, input.PermissionID, permission).
+		RemoveExpiration(input.RemoveExpiration).
+		TransferOwnership(input.TransferOwnership).
+		SupportsAllDrives(true).
+		Fields("id, type, role, emailAddress, domain, displayName, expirationTime").
+		Do()
+	if err != nil {
+		return nil, UpdateDrivePermissionOutput{}, fmt.Errorf("failed to update permission: %w", err)
+	}
+
+	resp := fmt.Sprintf("Permission updated successfully:\n  Grantee: %s (%s)\n  Role: %s\n  Permission ID: %s",
+		describeDrivePermission(updated), updated.Type, updated.Role, updated.Id)
+	if updated.ExpirationTime != "" {
+		resp += fmt.Sprintf("\n  Expires: %s", updated.ExpirationTime)
+	}
+
+	return nil, UpdateDrivePermissionOutput{Result: resp}, nil
+}
+
+// DeleteDrivePermission handles the delete_drive_permission tool call
+func DeleteDrivePermission(ctx context.Context, req *mcp.CallToolRequest, input DeleteDrivePermissionInput) (*mcp.CallToolResult, DeleteDrivePermissionOutput, error) {
+	srv, err := utils.NewDriveClient(input.Email)
+	if err != nil {
+		return nil, DeleteDrivePermissionOutput{}, err
+	}
+
+	err = srv.Permissions.Delete(input.FileID, input.PermissionID).
+		SupportsAllDrives(true).
+		Do()
+	if err != nil {
+		return nil, DeleteDrivePermissionOutput{}, fmt.Errorf("failed to delete permission: %w", err)
+	}
+
+	return nil, DeleteDrivePermissionOutput{Result: fmt.Sprintf("Permission %s removed from file %s", input.PermissionID, input.FileID)}, nil
+}
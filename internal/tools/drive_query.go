@@ -0,0 +1,105 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// driveQuery builds a Drive files.list search expression. Every value is
+// escaped before it is quoted, so user input can never terminate a string
+// literal and smuggle in extra clauses. Clauses are joined with "and".
+type driveQuery struct {
+	clauses []string
+}
+
+// quoteDriveQueryValue returns v as a single-quoted Drive query string literal.
+// Backslashes and single quotes are the only characters that need escaping.
+func quoteDriveQueryValue(v string) string {
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, `'`, `\'`)
+	return "'" + v + "'"
+}
+
+// NameContains matches files whose name contains v.
+func (q *driveQuery) NameContains(v string) *driveQuery {
+	q.clauses = append(q.clauses, "name contains "+quoteDriveQueryValue(v))
+	return q
+}
+
+// FullTextContains matches files whose name, description or content contains v.
+func (q *driveQuery) FullTextContains(v string) *driveQuery {
+	q.clauses = append(q.clauses, "fullText contains "+quoteDriveQueryValue(v))
+	return q
+}
+
+// MimeType matches files of exactly the given MIME type.
+func (q *driveQuery) MimeType(v string) *driveQuery {
+	q.clauses = append(q.clauses, "mimeType = "+quoteDriveQueryValue(v))
+	return q
+}
+
+// Owner matches files owned by the given email address.
+func (q *driveQuery) Owner(email string) *driveQuery {
+	q.clauses = append(q.clauses, quoteDriveQueryValue(email)+" in owners")
+	return q
+}
+
+// InParents matches direct children of the given folder.
+func (q *driveQuery) InParents(folderID string) *driveQuery {
+	q.clauses = append(q.clauses, quoteDriveQueryValue(folderID)+" in parents")
+	return q
+}
+
+// ModifiedAfter matches files modified strictly after t.
+func (q *driveQuery) ModifiedAfter(t time.Time) *driveQuery {
+	q.clauses = append(q.clauses, "modifiedTime > "+quoteDriveQueryValue(t.UTC().Format(time.RFC3339)))
+	return q
+}
+
+// ModifiedBefore matches files modified strictly before t.
+func (q *driveQuery) ModifiedBefore(t time.Time) *driveQuery {
+	q.clauses = append(q.clauses, "modifiedTime < "+quoteDriveQueryValue(t.UTC().Format(time.RFC3339)))
+	return q
+}
+
+// Starred matches files the user has starred.
+func (q *driveQuery) Starred() *driveQuery {
+	q.clauses = append(q.clauses, "starred = true")
+	return q
+}
+
+// SharedWithMe matches files in the user's "Shared with me" collection.
+func (q *driveQuery) SharedWithMe() *driveQuery {
+	q.clauses = append(q.clauses, "sharedWithMe = true")
+	return q
+}
+
+// AppProperty matches files whose private app property key has the given value.
+func (q *driveQuery) AppProperty(key, value string) *driveQuery {
+	q.clauses = append(q.clauses, fmt.Sprintf("appProperties has { key=%s and value=%s }", quoteDriveQueryValue(key), quoteDriveQueryValue(value)))
+	return q
+}
+
+// Trashed matches files by their trashed state.
+func (q *driveQuery) Trashed(trashed bool) *driveQuery {
+	q.clauses = append(q.clauses, fmt.Sprintf("trashed = %t", trashed))
+	return q
+}
+
+// String returns the query expression.
+func (q *driveQuery) String() string {
+	return strings.Join(q.clauses, " and ")
+}
+
+// parseDriveQueryTime parses an RFC3339 timestamp or a YYYY-MM-DD date for use
+// in a modifiedTime filter.
+func parseDriveQueryTime(field, v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.DateOnly, v); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid %s %q: expected RFC3339 timestamp or YYYY-MM-DD date", field, v)
+}
@@ -0,0 +1,349 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.orx.me/mcp/google-workspace/internal/utils"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+)
+
+// ListDriveRevisionsInput defines input for list_drive_revisions tool
+type ListDriveRevisionsInput struct {
+	Email  string `json:"email" jsonschema:"Email address to access Drive"`
+	FileID string `json:"fileId" jsonschema:"File ID whose revisions to list"`
+}
+
+// ListDriveRevisionsOutput defines output for list_drive_revisions tool
+type ListDriveRevisionsOutput struct {
+	Revisions string `json:"revisions" jsonschema:"List of revisions, oldest first"`
+}
+
+// GetDriveRevisionInput defines input for get_drive_revision tool
+type GetDriveRevisionInput struct {
+	Email          string `json:"email" jsonschema:"Email address to access Drive"`
+	FileID         string `json:"fileId" jsonschema:"File ID"`
+	RevisionID     string `json:"revisionId" jsonschema:"Revision ID (from list_drive_revisions)"`
+	ExportMimeType string `json:"exportMimeType,omitempty" jsonschema:"MIME type to export a Google Docs/Sheets/Slides revision as (default: text/plain for Docs, text/csv for Sheets)"`
+	Destination    string `json:"destination,omitempty" jsonschema:"Local directory inside the sandbox to save the revision into; when omitted, text content is returned inline"`
+	Overwrite      bool   `json:"overwrite,omitempty" jsonschema:"Overwrite a local file that already exists (default false)"`
+}
+
+// GetDriveRevisionOutput defines output for get_drive_revision tool
+type GetDriveRevisionOutput struct {
+	Result string `json:"result" jsonschema:"Revision metadata and its content or saved location"`
+}
+
+// PinDriveRevisionInput defines input for pin_drive_revision tool
+type PinDriveRevisionInput struct {
+	Email       string `json:"email" jsonschema:"Email address to access Drive"`
+	FileID      string `json:"fileId" jsonschema:"File ID"`
+	RevisionID  string `json:"revisionId" jsonschema:"Revision ID (from list_drive_revisions)"`
+	KeepForever bool   `json:"keepForever" jsonschema:"Set true to keep the revision forever, false to let Drive purge it automatically"`
+}
+
+// PinDriveRevisionOutput defines output for pin_drive_revision tool
+type PinDriveRevisionOutput struct {
+	Result string `json:"result" jsonschema:"Pin result"`
+}
+
+// RestoreDriveRevisionInput defines input for restore_drive_revision tool
+type RestoreDriveRevisionInput struct {
+	Email       string `json:"email" jsonschema:"Email address to access Drive"`
+	FileID      string `json:"fileId" jsonschema:"File ID"`
+	RevisionID  string `json:"revisionId" jsonschema:"Revision ID to restore (from list_drive_revisions)"`
+	KeepForever bool   `json:"keepForever,omitempty" jsonschema:"Keep the new head revision forever"`
+}
+
+// RestoreDriveRevisionOutput defines output for restore_drive_revision tool
+type RestoreDriveRevisionOutput struct {
+	Result string `json:"result" jsonschema:"Restore result"`
+}
+
+// maxInlineContentSize caps how much file content is returned inline to the model.
+const maxInlineContentSize = 1 << 20
+
+// defaultRevisionExportMimeTypes maps Google-native types to the text format
+// their revisions are exported as when no export type is requested.
+var defaultRevisionExportMimeTypes = map[string]string{
+	"application/vnd.google-apps.document":     "text/plain",
+	"application/vnd.google-apps.spreadsheet":  "text/csv",
+	"application/vnd.google-apps.presentation": "text/plain",
+	"application/vnd.google-apps.drawing":      "image/png",
+}
+
+// isTextMimeType reports whether content of the given MIME type is text that
+// can be returned inline.
+func isTextMimeType(mimeType string) bool {
+	mimeType = strings.ToLower(strings.TrimSpace(strings.SplitN(mimeType, ";", 2)[0]))
+	switch {
+	case strings.HasPrefix(mimeType, "text/"):
+		return true
+	case strings.HasSuffix(mimeType, "+json"), strings.HasSuffix(mimeType, "+xml"):
+		return true
+	}
+	switch mimeType {
+	case "application/json", "application/xml", "application/javascript", "application/x-yaml", "application/yaml", "application/sql":
+		return true
+	}
+	return false
+}
+
+// readInlineContent reads r as text, refusing content larger than maxInlineContentSize.
+func readInlineContent(r io.Reader) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxInlineContentSize+1))
+	if err != nil {
+		return "", err
+	}
+	if len(data) > maxInlineContentSize {
+		return "", fmt.Errorf("content is larger than %d bytes; set a destination to save it locally", maxInlineContentSize)
+	}
+	return string(data), nil
+}
+
+// updateDriveFileContent uploads r as the new content of fileID, creating a new
+// head revision. It is shared by every tool that rewrites a file's content.
+func updateDriveFileContent(ctx context.Context, call *drive.FilesUpdateCall, r io.Reader, mimeType string, keepForever bool) (*drive.File, error) {
+	var opts []googleapi.MediaOption
+	if mimeType != "" {
+		opts = append(opts, googleapi.ContentType(mimeType))
+	}
+	return call.
+		Media(r, opts...).
+		KeepRevisionForever(keepForever).
+		SupportsAllDrives(true).
+		Context(ctx).
+		Fields("id, name, mimeType, size, md5Checksum, modifiedTime, headRevisionId, webViewLink").
+		Do()
+}
+
+// ListDriveRevisions handles the list_drive_revisions tool call
+func ListDriveRevisions(ctx context.Context, req *mcp.CallToolRequest, input ListDriveRevisionsInput) (*mcp.CallToolResult, ListDriveRevisionsOutput, error) {
+	srv, err := utils.NewDriveClient(input.Email)
+	if err != nil {
+		return nil, ListDriveRevisionsOutput{}, err
+	}
+
+	var resp strings.Builder
+	count := 0
+	err = srv.Revisions.List(input.FileID).
+		PageSize(200).
+		Fields("nextPageToken, revisions(id, mimeType, modifiedTime, keepForever, size, md5Checksum, lastModifyingUser(displayName, emailAddress))").
+		Pages(ctx, func(page *drive.RevisionList) error {
+			for _, rev := range page.Revisions {
+				author := "unknown"
+				if rev.LastModifyingUser != nil {
+					author = rev.LastModifyingUser.DisplayName
+					if rev.LastModifyingUser.EmailAddress != "" {
+						author = fmt.Sprintf("%s <%s>", author, rev.LastModifyingUser.EmailAddress)
+					}
+				}
+				resp.WriteString(fmt.Sprintf("- Revision %s\n  Modified: %s\n  Author: %s\n", rev.Id, rev.ModifiedTime, author))
+				if rev.Size > 0 {
+					resp.WriteString(fmt.Sprintf("  Size: %d bytes\n", rev.Size))
+				}
+				if rev.KeepForever {
+					resp.WriteString("  Keep forever: true\n")
+				}
+				count++
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, ListDriveRevisionsOutput{}, fmt.Errorf("failed to list revisions: %w", err)
+	}
+
+	if count == 0 {
+		return nil, ListDriveRevisionsOutput{Revisions: "No revisions found."}, nil
+	}
+
+	return nil, ListDriveRevisionsOutput{Revisions: fmt.Sprintf("Revisions (%d):\n%s", count, resp.String())}, nil
+}
+
+// GetDriveRevision handles the get_drive_revision tool call
+func GetDriveRevision(ctx context.Context, req *mcp.CallToolRequest, input GetDriveRevisionInput) (*mcp.CallToolResult, GetDriveRevisionOutput, error) {
+	var dir string
+	if input.Destination != "" {
+		var err error
+		if dir, err = utils.SandboxPath(input.Destination); err != nil {
+			return nil, GetDriveRevisionOutput{}, err
+		}
+	}
+
+	srv, err := utils.NewDriveClient(input.Email)
+	if err != nil {
+		return nil, GetDriveRevisionOutput{}, err
+	}
+
+	file, err := srv.Files.Get(input.FileID).
+		SupportsAllDrives(true).
+		Fields("id, name, mimeType").
+		Do()
+	if err != nil {
+		return nil, GetDriveRevisionOutput{}, fmt.Errorf("failed to get file info: %w", err)
+	}
+
+	rev, err := srv.Revisions.Get(input.FileID, input.RevisionID).
+		Fields("id, mimeType, modifiedTime, size, md5Checksum, exportLinks, lastModifyingUser(displayName, emailAddress)").
+		Do()
+	if err != nil {
+		return nil, GetDriveRevisionOutput{}, fmt.Errorf("failed to get revision: %w", err)
+	}
+
+	var body *http.Response
+	contentType := rev.MimeType
+	if isGoogleNativeMimeType(file.MimeType) {
+		exportMime := input.ExportMimeType
+		if exportMime == "" {
+			exportMime = defaultRevisionExportMimeTypes[file.MimeType]
+		}
+		link := rev.ExportLinks[exportMime]
+		if link == "" {
+			return nil, GetDriveRevisionOutput{}, fmt.Errorf("revision %s cannot be exported as %q", rev.Id, exportMime)
+		}
+
+		client, err := utils.NewDriveHTTPClient(input.Email)
+		if err != nil {
+			return nil, GetDriveRevisionOutput{}, err
+		}
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+		if err != nil {
+			return nil, GetDriveRevisionOutput{}, err
+		}
+		body, err = client.Do(httpReq)
+		if err != nil {
+			return nil, GetDriveRevisionOutput{}, fmt.Errorf("failed to export revision: %w", err)
+		}
+		if err := googleapi.CheckResponse(body); err != nil {
+			body.Body.Close()
+			return nil, GetDriveRevisionOutput{}, fmt.Errorf("failed to export revision: %w", err)
+		}
+		contentType = exportMime
+	} else {
+		body, err = srv.Revisions.Get(input.FileID, input.RevisionID).Context(ctx).Download()
+		if err != nil {
+			return nil, GetDriveRevisionOutput{}, fmt.Errorf("failed to download revision: %w", err)
+		}
+	}
+	defer body.Body.Close()
+
+	var resp strings.Builder
+	resp.WriteString(fmt.Sprintf("Revision %s of %s\n  Modified: %s\n  Type: %s\n", rev.Id, file.Name, rev.ModifiedTime, contentType))
+	if rev.LastModifyingUser != nil {
+		resp.WriteString(fmt.Sprintf("  Author: %s\n", rev.LastModifyingUser.DisplayName))
+	}
+
+	if dir != "" {
+		name := localFileName(file.Name, file.Id)
+		ext := filepath.Ext(name)
+		if isGoogleNativeMimeType(file.MimeType) {
+			ext = exportExtensions[contentType]
+		}
+		target := filepath.Join(dir, fmt.Sprintf("%s (revision %s)%s", strings.TrimSuffix(name, filepath.Ext(name)), rev.Id, ext))
+		if !input.Overwrite {
+			if _, err := os.Stat(target); err == nil {
+				resp.WriteString(fmt.Sprintf("  Skipped: %s (already exists; set overwrite to replace it)\n", target))
+				return nil, GetDriveRevisionOutput{Result: resp.String()}, nil
+			}
+		}
+
+		size, sum, err := writeLocalFile(target, body.Body)
+		if err != nil {
+			return nil, GetDriveRevisionOutput{}, fmt.Errorf("failed to save revision: %w", err)
+		}
+		verified := "no checksum available"
+		if rev.Md5Checksum != "" {
+			if sum != rev.Md5Checksum {
+				os.Remove(target)
+				return nil, GetDriveRevisionOutput{}, fmt.Errorf("md5 mismatch for revision %s: expected %s, got %s", rev.Id, rev.Md5Checksum, sum)
+			}
+			verified = "md5 verified"
+		}
+		resp.WriteString(fmt.Sprintf("  Saved: %s (%d bytes, %s)\n", target, size, verified))
+		return nil, GetDriveRevisionOutput{Result: resp.String()}, nil
+	}
+
+	if !isTextMimeType(contentType) {
+		return nil, GetDriveRevisionOutput{}, fmt.Errorf("revision content is %s, not text; set a destination to save it locally", contentType)
+	}
+	content, err := readInlineContent(body.Body)
+	if err != nil {
+		return nil, GetDriveRevisionOutput{}, err
+	}
+	resp.WriteString("\nContent:\n")
+	resp.WriteString(content)
+
+	return nil, GetDriveRevisionOutput{Result: resp.String()}, nil
+}
+
+// PinDriveRevision handles the pin_drive_revision tool call
+func PinDriveRevision(ctx context.Context, req *mcp.CallToolRequest, input PinDriveRevisionInput) (*mcp.CallToolResult, PinDriveRevisionOutput, error) {
+	srv, err := utils.NewDriveClient(input.Email)
+	if err != nil {
+		return nil, PinDriveRevisionOutput{}, err
+	}
+
+	rev, err := srv.Revisions.Update(input.FileID, input.RevisionID, &drive.Revision{
+		KeepForever:     input.KeepForever,
+		ForceSendFields: []string{"KeepForever"},
+	}).
+		Fields("id, keepForever").
+		Do()
+	if err != nil {
+		return nil, PinDriveRevisionOutput{}, fmt.Errorf("failed to update revision: %w", err)
+	}
+
+	action := "unpinned"
+	if rev.KeepForever {
+		action = "pinned (keep forever)"
+	}
+
+	return nil, PinDriveRevisionOutput{Result: fmt.Sprintf("Revision %s %s", rev.Id, action)}, nil
+}
+
+// RestoreDriveRevision handles the restore_drive_revision tool call
+func RestoreDriveRevision(ctx context.Context, req *mcp.CallToolRequest, input RestoreDriveRevisionInput) (*mcp.CallToolResult, RestoreDriveRevisionOutput, error) {
+	srv, err := utils.NewDriveClient(input.Email)
+	if err != nil {
+		return nil, RestoreDriveRevisionOutput{}, err
+	}
+
+	file, err := srv.Files.Get(input.FileID).
+		SupportsAllDrives(true).
+		Fields("id, name, mimeType, headRevisionId").
+		Do()
+	if err != nil {
+		return nil, RestoreDriveRevisionOutput{}, fmt.Errorf("failed to get file info: %w", err)
+	}
+	if isGoogleNativeMimeType(file.MimeType) {
+		return nil, RestoreDriveRevisionOutput{}, fmt.Errorf("%s is a Google-native file; its revisions can only be restored from version history in Drive", file.Name)
+	}
+
+	rev, err := srv.Revisions.Get(input.FileID, input.RevisionID).Fields("id, mimeType").Do()
+	if err != nil {
+		return nil, RestoreDriveRevisionOutput{}, fmt.Errorf("failed to get revision: %w", err)
+	}
+
+	content, err := srv.Revisions.Get(input.FileID, input.RevisionID).Context(ctx).Download()
+	if err != nil {
+		return nil, RestoreDriveRevisionOutput{}, fmt.Errorf("failed to download revision: %w", err)
+	}
+	defer content.Body.Close()
+
+	updated, err := updateDriveFileContent(ctx, srv.Files.Update(input.FileID, &drive.File{}), content.Body, rev.MimeType, input.KeepForever)
+	if err != nil {
+		return nil, RestoreDriveRevisionOutput{}, fmt.Errorf("failed to restore revision: %w", err)
+	}
+
+	resp := fmt.Sprintf("Revision restored successfully:\n  File: %s\n  Restored revision: %s\n  Previous head revision: %s\n  New head revision: %s\n  Size: %d bytes",
+		updated.Name, input.RevisionID, file.HeadRevisionId, updated.HeadRevisionId, updated.Size)
+
+	return nil, RestoreDriveRevisionOutput{Result: resp}, nil
+}
@@ -0,0 +1,358 @@
+package tools
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.orx.me/mcp/google-workspace/internal/utils"
+	"google.golang.org/api/drive/v3"
+)
+
+// ListSharedDrivesInput defines input for list_shared_drives tool
+type ListSharedDrivesInput struct {
+	Email                string `json:"email" jsonschema:"Email address to access Drive"`
+	Query                string `json:"query,omitempty" jsonschema:"Only list shared drives whose name contains this text"`
+	UseDomainAdminAccess bool   `json:"useDomainAdminAccess,omitempty" jsonschema:"List every shared drive in the domain (requires the user to be a domain administrator)"`
+	MaxResults           int64  `json:"maxResults,omitempty" jsonschema:"Maximum number of shared drives to return (default 50)"`
+}
+
+// ListSharedDrivesOutput defines output for list_shared_drives tool
+type ListSharedDrivesOutput struct {
+	Drives string `json:"drives" jsonschema:"List of shared drives"`
+}
+
+// CreateSharedDriveInput defines input for create_shared_drive tool
+type CreateSharedDriveInput struct {
+	Email string `json:"email" jsonschema:"Email address to access Drive"`
+	Name  string `json:"name" jsonschema:"Name of the new shared drive"`
+}
+
+// CreateSharedDriveOutput defines output for create_shared_drive tool
+type CreateSharedDriveOutput struct {
+	Result string `json:"result" jsonschema:"Created shared drive information"`
+}
+
+// RenameSharedDriveInput defines input for rename_shared_drive tool
+type RenameSharedDriveInput struct {
+	Email                string `json:"email" jsonschema:"Email address to access Drive"`
+	DriveID              string `json:"driveId" jsonschema:"Shared drive ID"`
+	Name                 string `json:"name" jsonschema:"New name for the shared drive"`
+	UseDomainAdminAccess bool   `json:"useDomainAdminAccess,omitempty" jsonschema:"Act as a domain administrator rather than a drive member"`
+}
+
+// RenameSharedDriveOutput defines output for rename_shared_drive tool
+type RenameSharedDriveOutput struct {
+	Result string `json:"result" jsonschema:"Rename result"`
+}
+
+// HideSharedDriveInput defines input for hide_shared_drive tool
+type HideSharedDriveInput struct {
+	Email   string `json:"email" jsonschema:"Email address to access Drive"`
+	DriveID string `json:"driveId" jsonschema:"Shared drive ID"`
+	Hidden  bool   `json:"hidden" jsonschema:"Set true to hide the shared drive from the default view, false to show it again"`
+}
+
+// HideSharedDriveOutput defines output for hide_shared_drive tool
+type HideSharedDriveOutput struct {
+	Result string `json:"result" jsonschema:"Result of hiding or unhiding the shared drive"`
+}
+
+// ListSharedDriveMembersInput defines input for list_shared_drive_members tool
+type ListSharedDriveMembersInput struct {
+	Email                string `json:"email" jsonschema:"Email address to access Drive"`
+	DriveID              string `json:"driveId" jsonschema:"Shared drive ID"`
+	UseDomainAdminAccess bool   `json:"useDomainAdminAccess,omitempty" jsonschema:"Act as a domain administrator rather than a drive member"`
+}
+
+// ListSharedDriveMembersOutput defines output for list_shared_drive_members tool
+type ListSharedDriveMembersOutput struct {
+	Members string `json:"members" jsonschema:"List of shared drive members and their roles"`
+}
+
+// AddSharedDriveMemberInput defines input for add_shared_drive_member tool
+type AddSharedDriveMemberInput struct {
+	Email                string `json:"email" jsonschema:"Email address to access Drive"`
+	DriveID              string `json:"driveId" jsonschema:"Shared drive ID"`
+	MemberEmail          string `json:"memberEmail" jsonschema:"Email address of the user or group to add"`
+	MemberType           string `json:"memberType,omitempty" jsonschema:"Member type: user or group (default: user)"`
+	Role                 string `json:"role,omitempty" jsonschema:"Role: organizer, fileOrganizer, writer, commenter, reader (default: reader)"`
+	UseDomainAdminAccess bool   `json:"useDomainAdminAccess,omitempty" jsonschema:"Act as a domain administrator rather than a drive member"`
+}
+
+// AddSharedDriveMemberOutput defines output for add_shared_drive_member tool
+type AddSharedDriveMemberOutput struct {
+	Result string `json:"result" jsonschema:"Result of adding the member"`
+}
+
+// RemoveSharedDriveMemberInput defines input for remove_shared_drive_member tool
+type RemoveSharedDriveMemberInput struct {
+	Email                string `json:"email" jsonschema:"Email address to access Drive"`
+	DriveID              string `json:"driveId" jsonschema:"Shared drive ID"`
+	MemberEmail          string `json:"memberEmail" jsonschema:"Email address of the user or group to remove"`
+	UseDomainAdminAccess bool   `json:"useDomainAdminAccess,omitempty" jsonschema:"Act as a domain administrator rather than a drive member"`
+}
+
+// RemoveSharedDriveMemberOutput defines output for remove_shared_drive_member tool
+type RemoveSharedDriveMemberOutput struct {
+	Result string `json:"result" jsonschema:"Result of removing the member"`
+}
+
+// allDrivesList makes a files.list call see shared drives. When driveID is set
+// the call is limited to that shared drive; otherwise it searches My Drive and
+// every shared drive the user is a member of.
+func allDrivesList(call *drive.FilesListCall, driveID string) *drive.FilesListCall {
+	call = call.SupportsAllDrives(true).IncludeItemsFromAllDrives(true)
+	if driveID != "" {
+		return call.Corpora("drive").DriveId(driveID)
+	}
+	return call.Corpora("allDrives")
+}
+
+// newDriveRequestID returns a random ID that makes shared drive creation
+// idempotent when the request is retried.
+func newDriveRequestID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// ListSharedDrives handles the list_shared_drives tool call
+func ListSharedDrives(ctx context.Context, req *mcp.CallToolRequest, input ListSharedDrivesInput) (*mcp.CallToolResult, ListSharedDrivesOutput, error) {
+	srv, err := utils.NewDriveClient(input.Email)
+	if err != nil {
+		return nil, ListSharedDrivesOutput{}, err
+	}
+
+	maxResults := input.MaxResults
+	if maxResults == 0 {
+		maxResults = 50
+	}
+
+	call := srv.Drives.List().
+		PageSize(maxResults).
+		UseDomainAdminAccess(input.UseDomainAdminAccess).
+		Fields("drives(id, name, hidden, createdTime)")
+	if input.Query != "" {
+		call = call.Q("name contains " + quoteDriveQueryValue(input.Query))
+	}
+
+	drives, err := call.Do()
+	if err != nil {
+		return nil, ListSharedDrivesOutput{}, fmt.Errorf("failed to list shared drives: %w", err)
+	}
+
+	var resp strings.Builder
+	if len(drives.Drives) == 0 {
+		resp.WriteString("No shared drives found.\n")
+	} else {
+		resp.WriteString("Shared drives:\n")
+		for _, d := range drives.Drives {
+			hidden := ""
+			if d.Hidden {
+				hidden = " (hidden)"
+			}
+			resp.WriteString(fmt.Sprintf("- %s%s\n  ID: %s\n  Created: %s\n\n", d.Name, hidden, d.Id, d.CreatedTime))
+		}
+	}
+
+	return nil, ListSharedDrivesOutput{Drives: resp.String()}, nil
+}
+
+// CreateSharedDrive handles the create_shared_drive tool call
+func CreateSharedDrive(ctx context.Context, req *mcp.CallToolRequest, input CreateSharedDriveInput) (*mcp.CallToolResult, CreateSharedDriveOutput, error) {
+	srv, err := utils.NewDriveClient(input.Email)
+	if err != nil {
+		return nil, CreateSharedDriveOutput{}, err
+	}
+
+	requestID, err := newDriveRequestID()
+	if err != nil {
+		return nil, CreateSharedDriveOutput{}, fmt.Errorf("failed to generate request ID: %w", err)
+	}
+
+	created, err := srv.Drives.Create(requestID, &drive.Drive{Name: input.Name}).
+		Fields("id, name").
+		Do()
+	if err != nil {
+		return nil, CreateSharedDriveOutput{}, fmt.Errorf("failed to create shared drive: %w", err)
+	}
+
+	resp := fmt.Sprintf("Shared drive created successfully:\n  Name: %s\n  ID: %s", created.Name, created.Id)
+
+	return nil, CreateSharedDriveOutput{Result: resp}, nil
+}
+
+// RenameSharedDrive handles the rename_shared_drive tool call
+func RenameSharedDrive(ctx context.Context, req *mcp.CallToolRequest, input RenameSharedDriveInput) (*mcp.CallToolResult, RenameSharedDriveOutput, error) {
+	srv, err := utils.NewDriveClient(input.Email)
+	if err != nil {
+		return nil, RenameSharedDriveOutput{}, err
+	}
+
+	updated, err := srv.Drives.Update(input.DriveID, &drive.Drive{Name: input.Name}).
+		UseDomainAdminAccess(input.UseDomainAdminAccess).
+		Fields("id, name").
+		Do()
+	if err != nil {
+		return nil, RenameSharedDriveOutput{}, fmt.Errorf("failed to rename shared drive: %w", err)
+	}
+
+	resp := fmt.Sprintf("Shared drive renamed successfully:\n  Name: %s\n  ID: %s", updated.Name, updated.Id)
+
+	return nil, RenameSharedDriveOutput{Result: resp}, nil
+}
+
+// HideSharedDrive handles the hide_shared_drive tool call
+func HideSharedDrive(ctx context.Context, req *mcp.CallToolRequest, input HideSharedDriveInput) (*mcp.CallToolResult, HideSharedDriveOutput, error) {
+	srv, err := utils.NewDriveClient(input.Email)
+	if err != nil {
+		return nil, HideSharedDriveOutput{}, err
+	}
+
+	var d *drive.Drive
+	if input.Hidden {
+		d, err = srv.Drives.Hide(input.DriveID).Fields("id, name").Do()
+	} else {
+		d, err = srv.Drives.Unhide(input.DriveID).Fields("id, name").Do()
+	}
+	if err != nil {
+		return nil, HideSharedDriveOutput{}, fmt.Errorf("failed to update shared drive visibility: %w", err)
+	}
+
+	action := "shown"
+	if input.Hidden {
+		action = "hidden"
+	}
+
+	return nil, HideSharedDriveOutput{Result: fmt.Sprintf("Shared drive %s (%s) %s successfully", d.Name, d.Id, action)}, nil
+}
+
+// ListSharedDriveMembers handles the list_shared_drive_members tool call
+func ListSharedDriveMembers(ctx context.Context, req *mcp.CallToolRequest, input ListSharedDriveMembersInput) (*mcp.CallToolResult, ListSharedDriveMembersOutput, error) {
+	srv, err := utils.NewDriveClient(input.Email)
+	if err != nil {
+		return nil, ListSharedDriveMembersOutput{}, err
+	}
+
+	var resp strings.Builder
+	count := 0
+	err = srv.Permissions.List(input.DriveID).
+		SupportsAllDrives(true).
+		UseDomainAdminAccess(input.UseDomainAdminAccess).
+		Fields("nextPageToken, permissions(id, type, role, emailAddress, domain, displayName)").
+		Pages(ctx, func(page *drive.PermissionList) error {
+			for _, p := range page.Permissions {
+				who := p.EmailAddress
+				if who == "" {
+					who = p.Domain
+				}
+				resp.WriteString(fmt.Sprintf("- %s (%s)\n  Role: %s\n  Permission ID: %s\n", who, p.Type, p.Role, p.Id))
+				count++
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, ListSharedDriveMembersOutput{}, fmt.Errorf("failed to list shared drive members: %w", err)
+	}
+
+	if count == 0 {
+		return nil, ListSharedDriveMembersOutput{Members: "No members found."}, nil
+	}
+
+	return nil, ListSharedDriveMembersOutput{Members: fmt.Sprintf("Members (%d):\n%s", count, resp.String())}, nil
+}
+
+// AddSharedDriveMember handles the add_shared_drive_member tool call
+func AddSharedDriveMember(ctx context.Context, req *mcp.CallToolRequest, input AddSharedDriveMemberInput) (*mcp.CallToolResult, AddSharedDriveMemberOutput, error) {
+	memberType := input.MemberType
+	if memberType == "" {
+		memberType = "user"
+	}
+	if memberType != "user" && memberType != "group" {
+		return nil, AddSharedDriveMemberOutput{}, fmt.Errorf("invalid member type: %s (must be user or group)", memberType)
+	}
+
+	role := input.Role
+	if role == "" {
+		role = "reader"
+	}
+	validRoles := map[string]bool{
+		"organizer":     true,
+		"fileOrganizer": true,
+		"writer":        true,
+		"commenter":     true,
+		"reader":        true,
+	}
+	if !validRoles[role] {
+		return nil, AddSharedDriveMemberOutput{}, fmt.Errorf("invalid role: %s (must be organizer, fileOrganizer, writer, commenter, or reader)", role)
+	}
+
+	srv, err := utils.NewDriveClient(input.Email)
+	if err != nil {
+		return nil, AddSharedDriveMemberOutput{}, err
+	}
+
+	permission := &drive.Permission{
+		Type:         memberType,
+		Role:         role,
+		EmailAddress: input.MemberEmail,
+	}
+
+	created, err := srv.Permissions.Create(input.DriveID, permission).
+		SupportsAllDrives(true).
+		UseDomainAdminAccess(input.UseDomainAdminAccess).
+		Fields("id, role").
+		Do()
+	if err != nil {
+		return nil, AddSharedDriveMemberOutput{}, fmt.Errorf("failed to add shared drive member: %w", err)
+	}
+
+	resp := fmt.Sprintf("Member added successfully:\n  Member: %s\n  Role: %s\n  Permission ID: %s",
+		input.MemberEmail, created.Role, created.Id)
+
+	return nil, AddSharedDriveMemberOutput{Result: resp}, nil
+}
+
+// RemoveSharedDriveMember handles the remove_shared_drive_member tool call
+func RemoveSharedDriveMember(ctx context.Context, req *mcp.CallToolRequest, input RemoveSharedDriveMemberInput) (*mcp.CallToolResult, RemoveSharedDriveMemberOutput, error) {
+	srv, err := utils.NewDriveClient(input.Email)
+	if err != nil {
+		return nil, RemoveSharedDriveMemberOutput{}, err
+	}
+
+	var permissionID string
+	err = srv.Permissions.List(input.DriveID).
+		SupportsAllDrives(true).
+		UseDomainAdminAccess(input.UseDomainAdminAccess).
+		Fields("nextPageToken, permissions(id, emailAddress)").
+		Pages(ctx, func(page *drive.PermissionList) error {
+			for _, p := range page.Permissions {
+				if strings.EqualFold(p.EmailAddress, input.MemberEmail) {
+					permissionID = p.Id
+				}
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, RemoveSharedDriveMemberOutput{}, fmt.Errorf("failed to list shared drive members: %w", err)
+	}
+	if permissionID == "" {
+		return nil, RemoveSharedDriveMemberOutput{}, fmt.Errorf("%s is not a member of shared drive %s", input.MemberEmail, input.DriveID)
+	}
+
+	err = srv.Permissions.Delete(input.DriveID, permissionID).
+		SupportsAllDrives(true).
+		UseDomainAdminAccess(input.UseDomainAdminAccess).
+		Do()
+	if err != nil {
+		return nil, RemoveSharedDriveMemberOutput{}, fmt.Errorf("failed to remove shared drive member: %w", err)
+	}
+
+	return nil, RemoveSharedDriveMemberOutput{Result: fmt.Sprintf("Member %s removed from shared drive %s", input.MemberEmail, input.DriveID)}, nil
+}
@@ -0,0 +1,482 @@
+package tools
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.orx.me/mcp/google-workspace/internal/utils"
+	"google.golang.org/api/drive/v3"
+)
+
+// SyncDriveFolderInput defines input for sync_drive_folder tool
+type SyncDriveFolderInput struct {
+	Email     string `json:"email" jsonschema:"Email address to access Drive"`
+	FolderID  string `json:"folderId" jsonschema:"Drive folder ID to sync"`
+	LocalPath string `json:"localPath" jsonschema:"Local directory inside the sandbox to sync"`
+	Direction string `json:"direction" jsonschema:"Sync direction: download (Drive to local) or upload (local to Drive)"`
+	Apply     bool   `json:"apply,omitempty" jsonschema:"Apply the plan (default false: only report what would change)"`
+}
+
+// SyncDriveFolderOutput defines output for sync_drive_folder tool
+type SyncDriveFolderOutput struct {
+	Result string `json:"result" jsonschema:"Sync plan and, when applied, its results"`
+}
+
+// Operations in a sync plan.
+const (
+	syncOpMkdir  = "mkdir"
+	syncOpCreate = "create"
+	syncOpUpdate = "update"
+	syncOpSkip   = "skip"
+)
+
+// syncEntry is a file or folder on one side of a sync, keyed by its path
+// relative to the synced root using forward slashes.
+type syncEntry struct {
+	Path    string
+	IsDir   bool
+	Size    int64
+	MD5     string
+	ModTime time.Time
+	// LocalPath is set for local files so their checksum can be computed on demand.
+	LocalPath string
+	// File is set for Drive items.
+	File *drive.File
+}
+
+// syncAction is one step of a sync plan.
+type syncAction struct {
+	Op     string
+	Path   string
+	Reason string
+	Source *syncEntry
+	Dest   *syncEntry
+}
+
+// syncPlan is the result of comparing a sync source with its destination.
+type syncPlan struct {
+	Actions   []syncAction
+	Unchanged int
+	// Extra lists destination paths with no source counterpart; they are left untouched.
+	Extra []string
+}
+
+// entryChecksum returns the MD5 checksum of e, hashing local files on demand.
+func entryChecksum(e *syncEntry) (string, error) {
+	if e.MD5 != "" || e.LocalPath == "" {
+		return e.MD5, nil
+	}
+	f, err := os.Open(e.LocalPath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hash := md5.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	e.MD5 = hex.EncodeToString(hash.Sum(nil))
+	return e.MD5, nil
+}
+
+// planSync compares source with dest and returns the steps that make dest
+// mirror source. Files are compared by MD5 checksum when both sides have one,
+// and by modification time otherwise (Google-native files have no checksum).
+// Nothing is ever deleted from dest.
+func planSync(source, dest map[string]*syncEntry) (*syncPlan, error) {
+	paths := make([]string, 0, len(source))
+	for p := range source {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	plan := &syncPlan{}
+	for _, p := range paths {
+		src, dst := source[p], dest[p]
+		action := syncAction{Path: p, Source: src, Dest: dst}
+
+		switch {
+		case src.IsDir && dst == nil:
+			action.Op, action.Reason = syncOpMkdir, "missing in destination"
+		case src.IsDir && dst.IsDir:
+			continue
+		case dst == nil:
+			action.Op, action.Reason = syncOpCreate, "missing in destination"
+		case src.IsDir != dst.IsDir:
+			action.Op, action.Reason = syncOpSkip, "conflict: a file and a folder share this path"
+		case dst.File != nil && isGoogleNativeMimeType(dst.File.MimeType):
+			action.Op, action.Reason = syncOpSkip, "conflict: destination is a Google-native file"
+		default:
+			changed, reason, err := syncEntryChanged(src, dst)
+			if err != nil {
+				return nil, fmt.Errorf("failed to compare %s: %w", p, err)
+			}
+			if !changed {
+				plan.Unchanged++
+				continue
+			}
+			action.Op, action.Reason = syncOpUpdate, reason
+		}
+		plan.Actions = append(plan.Actions, action)
+	}
+
+	for p := range dest {
+		if _, ok := source[p]; !ok {
+			plan.Extra = append(plan.Extra, p)
+		}
+	}
+	sort.Strings(plan.Extra)
+
+	return plan, nil
+}
+
+// syncEntryChanged reports whether the file src differs from dst, and why.
+func syncEntryChanged(src, dst *syncEntry) (bool, string, error) {
+	canHash := (src.MD5 != "" || src.LocalPath != "") && (dst.MD5 != "" || dst.LocalPath != "")
+	if canHash {
+		if src.Size != dst.Size {
+			return true, fmt.Sprintf("size differs (%d -> %d bytes)", dst.Size, src.Size), nil
+		}
+		srcSum, err := entryChecksum(src)
+		if err != nil {
+			return false, "", err
+		}
+		dstSum, err := entryChecksum(dst)
+		if err != nil {
+			return false, "", err
+		}
+		if srcSum != dstSum {
+			return true, "md5 differs", nil
+		}
+		return false, "", nil
+	}
+
+	if src.ModTime.After(dst.ModTime) {
+		return true, "modified in source since the destination was written", nil
+	}
+	return false, "", nil
+}
+
+// listLocalSyncEntries walks root and returns its files and directories.
+// Symbolic links are not followed.
+func listLocalSyncEntries(root string) (map[string]*syncEntry, error) {
+	entries := map[string]*syncEntry{}
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == root || d.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), ".download-") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			entries[rel] = &syncEntry{Path: rel, IsDir: true}
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		entries[rel] = &syncEntry{Path: rel, Size: info.Size(), ModTime: info.ModTime(), LocalPath: p}
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	return entries, err
+}
+
+// driveSyncLister collects the contents of a Drive folder tree as sync entries.
+type driveSyncLister struct {
+	ctx context.Context
+	srv *drive.Service
+	// exportNames names Google-native files after their default export format,
+	// as they appear locally once downloaded.
+	exportNames bool
+
+	entries map[string]*syncEntry
+	notes   []string
+}
+
+// list adds the contents of folderID under the relative path prefix.
+func (l *driveSyncLister) list(folderID, prefix string) error {
+	var folders []*syncEntry
+	err := listDriveChildren(l.ctx, l.srv, folderID, "id, name, mimeType, size, md5Checksum, modifiedTime", func(f *drive.File) error {
+		name := localFileName(f.Name, f.Id)
+		if l.exportNames && isGoogleNativeMimeType(f.MimeType) && f.MimeType != driveFolderMimeType {
+			exportMime := defaultExportMimeTypes[f.MimeType]
+			if exportMime == "" {
+				l.notes = append(l.notes, fmt.Sprintf("%s: %s cannot be exported", path.Join(prefix, name), f.MimeType))
+				return nil
+			}
+			name += exportExtensions[exportMime]
+		}
+		p := path.Join(prefix, name)
+		if _, dup := l.entries[p]; dup {
+			l.notes = append(l.notes, fmt.Sprintf("%s: several Drive items share this name; only the first is synced", p))
+			return nil
+		}
+
+		modTime, _ := time.Parse(time.RFC3339, f.ModifiedTime)
+		entry := &syncEntry{Path: p, IsDir: f.MimeType == driveFolderMimeType, Size: f.Size, MD5: f.Md5Checksum, ModTime: modTime, File: f}
+		l.entries[p] = entry
+		if entry.IsDir {
+			folders = append(folders, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list folder %s: %w", folderID, err)
+	}
+
+	for _, folder := range folders {
+		if err := l.list(folder.File.Id, folder.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// driveSyncer applies a sync plan.
+type driveSyncer struct {
+	ctx  context.Context
+	srv  *drive.Service
+	root string
+	// folderIDs maps relative Drive folder paths to their IDs for uploads.
+	folderIDs map[string]string
+}
+
+// download writes the Drive file behind a to its local path and stamps it with
+// the Drive modification time so later syncs see it as unchanged. The local
+// listing skips symlinks, so a symlinked directory looks missing to the plan;
+// the target is checked against the sandbox again before anything is created.
+func (s *driveSyncer) download(a syncAction) error {
+	target := filepath.Join(s.root, filepath.FromSlash(a.Path))
+	if _, err := utils.SandboxPath(target); err != nil {
+		return err
+	}
+	if a.Op == syncOpMkdir {
+		return os.MkdirAll(target, 0o755)
+	}
+
+	file := a.Source.File
+	var body io.ReadCloser
+	if isGoogleNativeMimeType(file.MimeType) {
+		resp, err := s.srv.Files.Export(file.Id, defaultExportMimeTypes[file.MimeType]).Context(s.ctx).Download()
+		if err != nil {
+			return err
+		}
+		body = resp.Body
+	} else {
+		resp, err := s.srv.Files.Get(file.Id).SupportsAllDrives(true).Context(s.ctx).Download()
+		if err != nil {
+			return err
+		}
+		body = resp.Body
+	}
+	defer body.Close()
+
+	_, sum, err := writeLocalFile(target, body)
+	if err != nil {
+		return err
+	}
+	if file.Md5Checksum != "" && sum != file.Md5Checksum {
+		os.Remove(target)
+		return fmt.Errorf("md5 mismatch: expected %s, got %s", file.Md5Checksum, sum)
+	}
+	if !a.Source.ModTime.IsZero() {
+		return os.Chtimes(target, a.Source.ModTime, a.Source.ModTime)
+	}
+	return nil
+}
+
+// upload creates or updates the Drive counterpart of the local entry behind a.
+func (s *driveSyncer) upload(a syncAction) error {
+	dir := path.Dir(a.Path)
+	if dir == "." {
+		dir = ""
+	}
+	parentID, ok := s.folderIDs[dir]
+	if !ok {
+		return fmt.Errorf("parent folder %s was not created", dir)
+	}
+	name := path.Base(a.Path)
+
+	if a.Op == syncOpMkdir {
+		folder, err := s.srv.Files.Create(&drive.File{
+			Name:     name,
+			MimeType: driveFolderMimeType,
+			Parents:  []string{parentID},
+		}).
+			SupportsAllDrives(true).
+			Fields("id").
+			Do()
+		if err != nil {
+			return err
+		}
+		s.folderIDs[a.Path] = folder.Id
+		return nil
+	}
+
+	f, err := os.Open(a.Source.LocalPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	modTime := a.Source.ModTime.UTC().Format(time.RFC3339)
+	if a.Op == syncOpCreate {
+		_, err = s.srv.Files.Create(&drive.File{
+			Name:         name,
+			Parents:      []string{parentID},
+			ModifiedTime: modTime,
+		}).
+			Media(f).
+			SupportsAllDrives(true).
+			Context(s.ctx).
+			Fields("id").
+			Do()
+		return err
+	}
+
+	_, err = updateDriveFileContent(s.ctx, s.srv.Files.Update(a.Dest.File.Id, &drive.File{ModifiedTime: modTime}), f, "", false)
+	return err
+}
+
+// SyncDriveFolder handles the sync_drive_folder tool call
+func SyncDriveFolder(ctx context.Context, req *mcp.CallToolRequest, input SyncDriveFolderInput) (*mcp.CallToolResult, SyncDriveFolderOutput, error) {
+	if input.Direction != "download" && input.Direction != "upload" {
+		return nil, SyncDriveFolderOutput{}, fmt.Errorf("invalid direction: %s (must be download or upload)", input.Direction)
+	}
+
+	root, err := utils.SandboxPath(input.LocalPath)
+	if err != nil {
+		return nil, SyncDriveFolderOutput{}, err
+	}
+	if input.Direction == "upload" {
+		info, err := os.Stat(root)
+		if err != nil {
+			return nil, SyncDriveFolderOutput{}, fmt.Errorf("failed to read local directory: %w", err)
+		}
+		if !info.IsDir() {
+			return nil, SyncDriveFolderOutput{}, fmt.Errorf("%s is not a directory", input.LocalPath)
+		}
+	}
+
+	srv, err := utils.NewDriveClient(input.Email)
+	if err != nil {
+		return nil, SyncDriveFolderOutput{}, err
+	}
+
+	folder, err := srv.Files.Get(input.FolderID).
+		SupportsAllDrives(true).
+		Fields("id, name, mimeType").
+		Do()
+	if err != nil {
+		return nil, SyncDriveFolderOutput{}, fmt.Errorf("failed to get folder info: %w", err)
+	}
+	if folder.MimeType != driveFolderMimeType {
+		return nil, SyncDriveFolderOutput{}, fmt.Errorf("%s (%s) is not a folder", folder.Name, folder.MimeType)
+	}
+
+	lister := &driveSyncLister{ctx: ctx, srv: srv, exportNames: input.Direction == "download", entries: map[string]*syncEntry{}}
+	if err := lister.list(folder.Id, ""); err != nil {
+		return nil, SyncDriveFolderOutput{}, err
+	}
+	local, err := listLocalSyncEntries(root)
+	if err != nil {
+		return nil, SyncDriveFolderOutput{}, fmt.Errorf("failed to list local directory: %w", err)
+	}
+
+	source, dest := lister.entries, local
+	if input.Direction == "upload" {
+		source, dest = local, lister.entries
+	}
+	plan, err := planSync(source, dest)
+	if err != nil {
+		return nil, SyncDriveFolderOutput{}, err
+	}
+
+	var resp strings.Builder
+	if input.Direction == "download" {
+		resp.WriteString(fmt.Sprintf("Sync download: Drive folder %s (%s) -> %s\n", folder.Name, folder.Id, root))
+	} else {
+		resp.WriteString(fmt.Sprintf("Sync upload: %s -> Drive folder %s (%s)\n", root, folder.Name, folder.Id))
+	}
+	resp.WriteString(fmt.Sprintf("Plan: %d change(s), %d unchanged, %d only in destination (left untouched)\n\n", len(plan.Actions), plan.Unchanged, len(plan.Extra)))
+	for _, a := range plan.Actions {
+		resp.WriteString(fmt.Sprintf("- %s %s (%s)\n", a.Op, a.Path, a.Reason))
+	}
+	if len(plan.Extra) > 0 {
+		resp.WriteString("\nOnly in destination:\n")
+		for _, p := range plan.Extra {
+			resp.WriteString(fmt.Sprintf("- %s\n", p))
+		}
+	}
+	if len(lister.notes) > 0 {
+		resp.WriteString("\nNot synced:\n")
+		for _, n := range lister.notes {
+			resp.WriteString(fmt.Sprintf("- %s\n", n))
+		}
+	}
+
+	if !input.Apply {
+		resp.WriteString("\nNothing was changed; set apply to true to carry out this plan.\n")
+		return nil, SyncDriveFolderOutput{Result: resp.String()}, nil
+	}
+
+	syncer := &driveSyncer{ctx: ctx, srv: srv, root: root, folderIDs: map[string]string{"": folder.Id}}
+	for p, e := range lister.entries {
+		if e.IsDir {
+			syncer.folderIDs[p] = e.File.Id
+		}
+	}
+
+	applied, failed := 0, 0
+	var failures strings.Builder
+	for i, a := range plan.Actions {
+		if a.Op == syncOpSkip {
+			continue
+		}
+		notifyProgress(ctx, req, float64(i), float64(len(plan.Actions)), fmt.Sprintf("%s %s", a.Op, a.Path))
+		if input.Direction == "download" {
+			err = syncer.download(a)
+		} else {
+			err = syncer.upload(a)
+		}
+		if err != nil {
+			failed++
+			failures.WriteString(fmt.Sprintf("- %s %s: %v\n", a.Op, a.Path, err))
+			continue
+		}
+		applied++
+	}
+
+	resp.WriteString(fmt.Sprintf("\nApplied: %d, failed: %d\n", applied, failed))
+	if failed > 0 {
+		resp.WriteString(failures.String())
+	}
+
+	return nil, SyncDriveFolderOutput{Result: resp.String()}, nil
+}
@@ -0,0 +1,765 @@
+package tools
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/drive/v3"
+	"pgregory.net/rapid"
+)
+
+// ============================================================================
+// Drive Input Validation Tests
+// ============================================================================
+
+// driveInputStructs returns all Drive Input structs that need to be validated
+func driveInputStructs() []any {
+	return []any{
+		ListDriveFilesInput{},
+		SearchDriveFilesInput{},
+		GetDriveFileInput{},
+		CreateDriveFolderInput{},
+		UploadDriveFileInput{},
+		ShareDriveFileInput{},
+		DownloadDriveFileInput{},
+		ListSharedDrivesInput{},
+		CreateSharedDriveInput{},
+		RenameSharedDriveInput{},
+		HideSharedDriveInput{},
+		ListSharedDriveMembersInput{},
+		AddSharedDriveMemberInput{},
+		RemoveSharedDriveMemberInput{},
+		RenameDriveFileInput{},
+		MoveDriveFileInput{},
+		CopyDriveFileInput{},
+		TrashDriveFileInput{},
+		RestoreDriveFileInput{},
+		DeleteDriveFileInput{},
+		EmptyDriveTrashInput{},
+		ListDrivePermissionsInput{},
+		UpdateDrivePermissionInput{},
+		DeleteDrivePermissionInput{},
+		AuditDriveSharingInput{},
+		ListDriveRevisionsInput{},
+		GetDriveRevisionInput{},
+		PinDriveRevisionInput{},
+		RestoreDriveRevisionInput{},
+		ListDriveCommentsInput{},
+		CreateDriveCommentInput{},
+		ReplyDriveCommentInput{},
+		ResolveDriveCommentInput{},
+		UpdateDriveFileContentInput{},
+		GetDriveFolderTreeInput{},
+		SyncDriveFolderInput{},
+		GetDriveStorageQuotaInput{},
+		GetDriveUsageBreakdownInput{},
+		CreateDriveShortcutInput{},
+		SetDriveFileMetadataInput{},
+	}
+}
+
+// TestDriveInputStructTagCompleteness verifies that all Drive Input structs
+// have proper json and jsonschema tags on all fields
+func TestDriveInputStructTagCompleteness(t *testing.T) {
+	for _, input := range driveInputStructs() {
+		structType := reflect.TypeOf(input)
+		structName := structType.Name()
+
+		t.Run(structName, func(t *testing.T) {
+			for i := range structType.NumField() {
+				field := structType.Field(i)
+
+				if field.Tag.Get("json") == "" {
+					t.Errorf("Field %s.%s is missing json tag", structName, field.Name)
+				}
+				if field.Tag.Get("jsonschema") == "" {
+					t.Errorf("Field %s.%s is missing jsonschema tag", structName, field.Name)
+				}
+			}
+		})
+	}
+}
+
+// TestDriveRequiredFieldsHaveRequiredTag verifies that required Input fields
+// are not marked omitempty in their json tag
+func TestDriveRequiredFieldsHaveRequiredTag(t *testing.T) {
+	requiredFields := map[string][]string{
+		"ListDriveFilesInput":          {"Email"},
+		"SearchDriveFilesInput":        {"Email"},
+		"GetDriveFileInput":            {"Email", "FileID"},
+		"CreateDriveFolderInput":       {"Email", "Name"},
+		"UploadDriveFileInput":         {"Email", "FilePath"},
+		"ShareDriveFileInput":          {"Email", "FileID"},
+		"DownloadDriveFileInput":       {"Email", "FileID"},
+		"ListSharedDrivesInput":        {"Email"},
+		"CreateSharedDriveInput":       {"Email", "Name"},
+		"RenameSharedDriveInput":       {"Email", "DriveID", "Name"},
+		"HideSharedDriveInput":         {"Email", "DriveID", "Hidden"},
+		"ListSharedDriveMembersInput":  {"Email", "DriveID"},
+		"AddSharedDriveMemberInput":    {"Email", "DriveID", "MemberEmail"},
+		"RemoveSharedDriveMemberInput": {"Email", "DriveID", "MemberEmail"},
+		"RenameDriveFileInput":         {"Email", "FileID", "Name"},
+		"MoveDriveFileInput":           {"Email", "FileID", "DestinationID"},
+		"CopyDriveFileInput":           {"Email", "FileID"},
+		"TrashDriveFileInput":          {"Email", "FileID"},
+		"RestoreDriveFileInput":        {"Email", "FileID"},
+		"DeleteDriveFileInput":         {"Email", "FileID", "Confirm"},
+		"EmptyDriveTrashInput":         {"Email", "Confirm"},
+		"ListDrivePermissionsInput":    {"Email", "FileID"},
+		"UpdateDrivePermissionInput":   {"Email", "FileID", "PermissionID"},
+		"DeleteDrivePermissionInput":   {"Email", "FileID", "PermissionID"},
+		"ListDriveRevisionsInput":      {"Email", "FileID"},
+		"GetDriveRevisionInput":        {"Email", "FileID", "RevisionID"},
+		"PinDriveRevisionInput":        {"Email", "FileID", "RevisionID", "KeepForever"},
+		"RestoreDriveRevisionInput":    {"Email", "FileID", "RevisionID"},
+		"ListDriveCommentsInput":       {"Email", "FileID"},
+		"CreateDriveCommentInput":      {"Email", "FileID", "Content"},
+		"ReplyDriveCommentInput":       {"Email", "FileID", "CommentID", "Content"},
+		"ResolveDriveCommentInput":     {"Email", "FileID", "CommentID", "Resolved"},
+		"UpdateDriveFileContentInput":  {"Email", "FileID"},
+		"GetDriveFolderTreeInput":      {"Email", "FolderID"},
+		"SyncDriveFolderInput":         {"Email", "FolderID", "LocalPath", "Direction"},
+		"GetDriveStorageQuotaInput":    {},
+		"GetDriveUsageBreakdownInput":  {"Email"},
+		"CreateDriveShortcutInput":     {"Email", "TargetID"},
+		"SetDriveFileMetadataInput":    {"Email", "FileID"},
+	}
+
+	for _, input := range driveInputStructs() {
+		structType := reflect.TypeOf(input)
+		structName := structType.Name()
+
+		t.Run(structName, func(t *testing.T) {
+			expectedRequired, ok := requiredFields[structName]
+			if !ok {
+				t.Skipf("No required fields defined for %s", structName)
+				return
+			}
+
+			for _, fieldName := range expectedRequired {
+				field, found := structType.FieldByName(fieldName)
+				if !found {
+					t.Errorf("Expected required field %s not found in %s", fieldName, structName)
+					continue
+				}
+
+				jsonTag := field.Tag.Get("json")
+				if strings.Contains(jsonTag, "omitempty") || strings.Contains(jsonTag, "omitzero") {
+					t.Errorf("Required field %s.%s should not be omitempty in json tag", structName, fieldName)
+				}
+			}
+		})
+	}
+}
+
+// TestNewDrivePermission verifies validation of sharing options
+func TestNewDrivePermission(t *testing.T) {
+	testCases := []struct {
+		name       string
+		permType   string
+		role       string
+		email      string
+		domain     string
+		discovery  bool
+		expiration string
+		wantErr    bool
+	}{
+		{name: "user reader", permType: "user", role: "reader", email: "bob@example.com"},
+		{name: "group writer with expiry", permType: "group", role: "writer", email: "team@example.com", expiration: "2030-01-01T00:00:00Z"},
+		{name: "domain discoverable", permType: "domain", role: "reader", domain: "example.com", discovery: true},
+		{name: "anyone with link", permType: "anyone", role: "commenter"},
+		{name: "owner transfer", permType: "user", role: "owner", email: "bob@example.com"},
+		{name: "user without email", permType: "user", role: "reader", wantErr: true},
+		{name: "domain without domain", permType: "domain", role: "reader", wantErr: true},
+		{name: "unknown type", permType: "everyone", role: "reader", wantErr: true},
+		{name: "unknown role", permType: "user", role: "admin", email: "bob@example.com", wantErr: true},
+		{name: "owner for group", permType: "group", role: "owner", email: "team@example.com", wantErr: true},
+		{name: "discovery for user", permType: "user", role: "reader", email: "bob@example.com", discovery: true, wantErr: true},
+		{name: "expiry for anyone", permType: "anyone", role: "reader", expiration: "2030-01-01T00:00:00Z", wantErr: true},
+		{name: "expiry for owner", permType: "user", role: "owner", email: "bob@example.com", expiration: "2030-01-01T00:00:00Z", wantErr: true},
+		{name: "bad expiry", permType: "user", role: "reader", email: "bob@example.com", expiration: "tomorrow", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := newDrivePermission(tc.permType, tc.role, tc.email, tc.domain, tc.discovery, tc.expiration)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("Expected error, got permission %+v", p)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if p.Type != tc.permType || p.Role != tc.role {
+				t.Errorf("permission = %s/%s, want %s/%s", p.Type, p.Role, tc.permType, tc.role)
+			}
+		})
+	}
+}
+
+// TestClassifyDrivePermission verifies which permissions the sharing audit flags
+func TestClassifyDrivePermission(t *testing.T) {
+	internal := map[string]bool{"example.com": true}
+
+	testCases := []struct {
+		name       string
+		permission *drive.Permission
+		want       []string
+	}{
+		{
+			name:       "internal user",
+			permission: &drive.Permission{Type: "user", Role: "writer", EmailAddress: "bob@example.com"},
+			want:       nil,
+		},
+		{
+			name:       "internal user mixed case",
+			permission: &drive.Permission{Type: "user", Role: "reader", EmailAddress: "Bob@EXAMPLE.com"},
+			want:       nil,
+		},
+		{
+			name:       "external reader",
+			permission: &drive.Permission{Type: "user", Role: "reader", EmailAddress: "eve@other.org"},
+			want:       []string{sharingFindingExternal},
+		},
+		{
+			name:       "external writer",
+			permission: &drive.Permission{Type: "group", Role: "writer", EmailAddress: "team@other.org"},
+			want:       []string{sharingFindingExternal, sharingFindingWriter},
+		},
+		{
+			name:       "anyone reader",
+			permission: &drive.Permission{Type: "anyone", Role: "reader"},
+			want:       []string{sharingFindingAnyone},
+		},
+		{
+			name:       "anyone writer",
+			permission: &drive.Permission{Type: "anyone", Role: "writer"},
+			want:       []string{sharingFindingAnyone, sharingFindingWriter},
+		},
+		{
+			name:       "internal domain reader",
+			permission: &drive.Permission{Type: "domain", Role: "reader", Domain: "example.com"},
+			want:       nil,
+		},
+		{
+			name:       "internal domain writer",
+			permission: &drive.Permission{Type: "domain", Role: "writer", Domain: "example.com"},
+			want:       []string{sharingFindingWriter},
+		},
+		{
+			name:       "external domain",
+			permission: &drive.Permission{Type: "domain", Role: "commenter", Domain: "partner.com"},
+			want:       []string{sharingFindingExternal},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := classifyDrivePermission(tc.permission, internal)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("classifyDrivePermission() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// TestIsTextMimeType verifies which content types are returned inline
+func TestIsTextMimeType(t *testing.T) {
+	testCases := map[string]bool{
+		"text/plain":               true,
+		"text/csv; charset=utf-8":  true,
+		"application/json":         true,
+		"application/vnd.api+json": true,
+		"image/svg+xml":            true,
+		"application/pdf":          false,
+		"image/png":                false,
+		"application/octet-stream": false,
+		"application/vnd.ms-excel": false,
+		"TEXT/MARKDOWN":            true,
+	}
+
+	for mimeType, want := range testCases {
+		if got := isTextMimeType(mimeType); got != want {
+			t.Errorf("isTextMimeType(%q) = %t, want %t", mimeType, got, want)
+		}
+	}
+}
+
+// TestFormatDriveComment verifies quoted text, status and replies are rendered
+func TestFormatDriveComment(t *testing.T) {
+	c := &drive.Comment{
+		Id:                "c1",
+		Content:           "Please reword",
+		Resolved:          true,
+		Author:            &drive.User{DisplayName: "Ann", EmailAddress: "ann@example.com"},
+		QuotedFileContent: &drive.CommentQuotedFileContent{Value: "old text"},
+		Replies: []*drive.Reply{
+			{Id: "r1", Content: "Done", Action: "resolve", Author: &drive.User{DisplayName: "Bob"}},
+			{Id: "r2", Deleted: true},
+		},
+	}
+
+	got := formatDriveComment(c)
+	for _, want := range []string{"Comment c1 (resolved)", "Ann <ann@example.com>", `Quoted text: "old text"`, "Reply r1 by Bob", "[resolve]: Done"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("formatDriveComment() missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "r2") {
+		t.Errorf("formatDriveComment() should skip deleted replies:\n%s", got)
+	}
+}
+
+// TestCheckDriveFileUnchanged verifies the optimistic concurrency check
+func TestCheckDriveFileUnchanged(t *testing.T) {
+	file := &drive.File{Name: "notes.md", HeadRevisionId: "rev2", ModifiedTime: "2025-03-01T10:00:00.000Z"}
+
+	testCases := []struct {
+		name     string
+		revision string
+		modified string
+		wantErr  bool
+	}{
+		{"no expectations", "", "", false},
+		{"matching revision", "rev2", "", false},
+		{"stale revision", "rev1", "", true},
+		{"matching time", "", "2025-03-01T10:00:00Z", false},
+		{"matching time in other zone", "", "2025-03-01T11:00:00+01:00", false},
+		{"stale time", "", "2025-03-01T09:59:59Z", true},
+		{"invalid time", "", "yesterday", true},
+		{"revision and stale time", "rev2", "2025-02-01T10:00:00Z", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkDriveFileUnchanged(file, tc.revision, tc.modified)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("checkDriveFileUnchanged() error = %v, wantErr %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+// TestPlanSync verifies how source and destination entries turn into sync steps
+func TestPlanSync(t *testing.T) {
+	older := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+
+	source := map[string]*syncEntry{
+		"docs":             {Path: "docs", IsDir: true},
+		"docs/new.txt":     {Path: "docs/new.txt", Size: 3, MD5: "aaa"},
+		"same.txt":         {Path: "same.txt", Size: 3, MD5: "bbb"},
+		"edited.txt":       {Path: "edited.txt", Size: 3, MD5: "ccc"},
+		"grown.txt":        {Path: "grown.txt", Size: 9, MD5: "ddd"},
+		"report.docx":      {Path: "report.docx", ModTime: newer},
+		"plan.docx":        {Path: "plan.docx", ModTime: older},
+		"clash":            {Path: "clash", Size: 1, MD5: "eee"},
+		"native-conflict":  {Path: "native-conflict", Size: 1, MD5: "fff"},
+		"existing":         {Path: "existing", IsDir: true},
+		"existing/old.txt": {Path: "existing/old.txt", Size: 1, MD5: "ggg"},
+	}
+	dest := map[string]*syncEntry{
+		"same.txt":         {Path: "same.txt", Size: 3, MD5: "bbb"},
+		"edited.txt":       {Path: "edited.txt", Size: 3, MD5: "xxx"},
+		"grown.txt":        {Path: "grown.txt", Size: 3, MD5: "ddd"},
+		"report.docx":      {Path: "report.docx", ModTime: older},
+		"plan.docx":        {Path: "plan.docx", ModTime: older},
+		"clash":            {Path: "clash", IsDir: true},
+		"native-conflict":  {Path: "native-conflict", File: &drive.File{MimeType: "application/vnd.google-apps.document"}},
+		"existing":         {Path: "existing", IsDir: true},
+		"existing/old.txt": {Path: "existing/old.txt", Size: 1, MD5: "ggg"},
+		"stray.txt":        {Path: "stray.txt", Size: 1, MD5: "hhh"},
+	}
+
+	plan, err := planSync(source, dest)
+	if err != nil {
+		t.Fatalf("planSync() error = %v", err)
+	}
+
+	got := map[string]string{}
+	for _, a := range plan.Actions {
+		got[a.Path] = a.Op
+	}
+	want := map[string]string{
+		"clash":           syncOpSkip,
+		"docs":            syncOpMkdir,
+		"docs/new.txt":    syncOpCreate,
+		"edited.txt":      syncOpUpdate,
+		"grown.txt":       syncOpUpdate,
+		"native-conflict": syncOpSkip,
+		"report.docx":     syncOpUpdate,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("planSync() actions = %v, want %v", got, want)
+	}
+	if plan.Unchanged != 3 {
+		t.Errorf("planSync() unchanged = %d, want 3", plan.Unchanged)
+	}
+	if !reflect.DeepEqual(plan.Extra, []string{"stray.txt"}) {
+		t.Errorf("planSync() extra = %v, want [stray.txt]", plan.Extra)
+	}
+	if plan.Actions[0].Path != "clash" || plan.Actions[1].Path != "docs" || plan.Actions[2].Path != "docs/new.txt" {
+		t.Errorf("planSync() actions should be ordered by path so folders precede their contents")
+	}
+}
+
+// TestSyncEntryChangedHashesLocalFiles verifies local files are hashed on demand
+func TestSyncEntryChangedHashesLocalFiles(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "a.txt")
+	if err := os.WriteFile(p, []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	local := &syncEntry{Path: "a.txt", Size: 5, LocalPath: p}
+	remote := &syncEntry{Path: "a.txt", Size: 5, MD5: "5d41402abc4b2a76b9719d911017c592"}
+	changed, _, err := syncEntryChanged(local, remote)
+	if err != nil || changed {
+		t.Errorf("syncEntryChanged() = %t, %v; want unchanged", changed, err)
+	}
+
+	remote.MD5 = "00000000000000000000000000000000"
+	local.MD5 = ""
+	changed, reason, err := syncEntryChanged(local, remote)
+	if err != nil || !changed || reason != "md5 differs" {
+		t.Errorf("syncEntryChanged() = %t, %q, %v; want md5 differs", changed, reason, err)
+	}
+}
+
+// TestAggregateDriveUsage verifies sizes are grouped by type, owner and top-level folder,
+// that only owned files count toward the total, and that unlisted parents are looked up once
+func TestAggregateDriveUsage(t *testing.T) {
+	owner := []*drive.User{{EmailAddress: "ann@example.com"}}
+	folders := map[string]*drive.File{
+		"projects": {Id: "projects", Name: "Projects", MimeType: driveFolderMimeType, Parents: []string{"root-id"}},
+		"alpha":    {Id: "alpha", Name: "Alpha", MimeType: driveFolderMimeType, Parents: []string{"projects"}},
+	}
+	files := []*drive.File{
+		folders["projects"],
+		folders["alpha"],
+		{Name: "a.pdf", MimeType: "application/pdf", Size: 100, Parents: []string{"alpha"}, Owners: owner, OwnedByMe: true},
+		{Name: "b.pdf", MimeType: "application/pdf", Size: 50, Parents: []string{"root-id"}, Owners: owner, OwnedByMe: true},
+		{Name: "c.png", MimeType: "image/png", Size: 25, Parents: []string{"someone-elses"}, Owners: []*drive.User{{EmailAddress: "bob@example.com"}}},
+		{Name: "d.txt", MimeType: "text/plain", Size: 5},
+		// Owned files inside a folder someone else owns, under the user's My Drive.
+		{Name: "e.mp4", MimeType: "video/mp4", Size: 400, Parents: []string{"team"}, Owners: owner, OwnedByMe: true},
+		{Name: "f.mp4", MimeType: "video/mp4", Size: 600, Parents: []string{"team"}, Owners: owner, OwnedByMe: true},
+	}
+
+	lookups := map[string]int{}
+	lookup := func(id string) (*drive.File, error) {
+		lookups[id]++
+		if id == "team" {
+			return &drive.File{Id: "team", Name: "Team", Parents: []string{"root-id"}}, nil
+		}
+		return nil, errors.New("file not found: " + id)
+	}
+	usage := aggregateDriveUsage(files, folders, "root-id", lookup)
+
+	if usage.files != 6 || usage.owned != 4 || usage.total != 1150 {
+		t.Errorf("files = %d, owned = %d, total = %d; want 6, 4, 1150", usage.files, usage.owned, usage.total)
+	}
+	if want := map[string]int64{"application/pdf": 150, "image/png": 25, "text/plain": 5, "video/mp4": 1000}; !reflect.DeepEqual(usage.byType, want) {
+		t.Errorf("byType = %v, want %v", usage.byType, want)
+	}
+	if want := map[string]int64{"ann@example.com": 1150, "bob@example.com": 25, "(no owner: shared drive)": 5}; !reflect.DeepEqual(usage.byOwner, want) {
+		t.Errorf("byOwner = %v, want %v", usage.byOwner, want)
+	}
+	if want := map[string]int64{"Projects/": 100, "My Drive": 50, "Team/": 1000, outsideMyDrive: 30}; !reflect.DeepEqual(usage.byFolder, want) {
+		t.Errorf("byFolder = %v, want %v", usage.byFolder, want)
+	}
+	if want := map[string]int{"team": 1, "someone-elses": 1}; !reflect.DeepEqual(lookups, want) {
+		t.Errorf("lookups = %v, want %v", lookups, want)
+	}
+}
+
+// TestFormatBytes verifies byte counts are rendered with binary units
+func TestFormatBytes(t *testing.T) {
+	testCases := map[int64]string{
+		0:               "0 B",
+		1023:            "1023 B",
+		1024:            "1.0 KiB",
+		1536:            "1.5 KiB",
+		5 * 1024 * 1024: "5.0 MiB",
+		3 << 40:         "3.0 TiB",
+	}
+	for n, want := range testCases {
+		if got := formatBytes(n); got != want {
+			t.Errorf("formatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+// TestResultCacheExpires verifies cached results are dropped after the TTL
+func TestResultCacheExpires(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := newResultCache(time.Minute)
+	cache.now = func() time.Time { return now }
+
+	cache.set("k", "v")
+	if got, _, ok := cache.get("k"); !ok || got != "v" {
+		t.Fatalf("get() = %q, %t; want v, true", got, ok)
+	}
+
+	now = now.Add(59 * time.Second)
+	if _, _, ok := cache.get("k"); !ok {
+		t.Error("get() should hit before the TTL elapses")
+	}
+
+	now = now.Add(time.Second)
+	if _, _, ok := cache.get("k"); ok {
+		t.Error("get() should miss once the TTL elapses")
+	}
+	if _, _, ok := cache.get("missing"); ok {
+		t.Error("get() should miss for unknown keys")
+	}
+}
+
+// TestNewDriveFileMetadataUpdate verifies metadata changes are validated and
+// that explicit false and empty values are still sent
+func TestNewDriveFileMetadataUpdate(t *testing.T) {
+	unstar, empty := false, ""
+
+	update, err := newDriveFileMetadataUpdate(SetDriveFileMetadataInput{
+		Starred:             &unstar,
+		Description:         &empty,
+		FolderColorRgb:      "#FF0000",
+		AppProperties:       map[string]string{"team": "ops"},
+		RemoveAppProperties: []string{"stale"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(update.ForceSendFields, []string{"Starred", "Description"}) {
+		t.Errorf("ForceSendFields = %v", update.ForceSendFields)
+	}
+	if !reflect.DeepEqual(update.NullFields, []string{"AppProperties.stale"}) {
+		t.Errorf("NullFields = %v", update.NullFields)
+	}
+	if update.FolderColorRgb != "#ff0000" {
+		t.Errorf("FolderColorRgb = %q, want #ff0000", update.FolderColorRgb)
+	}
+
+	invalid := []SetDriveFileMetadataInput{
+		{},
+		{FolderColorRgb: "red"},
+		{AppProperties: map[string]string{"k": "v"}, RemoveAppProperties: []string{"k"}},
+	}
+	for _, input := range invalid {
+		if _, err := newDriveFileMetadataUpdate(input); err == nil {
+			t.Errorf("newDriveFileMetadataUpdate(%+v) should fail", input)
+		}
+	}
+}
+
+// TestFormatDriveListEntryResolvesShortcuts verifies shortcuts show their target
+func TestFormatDriveListEntryResolvesShortcuts(t *testing.T) {
+	got := formatDriveListEntry(&drive.File{
+		Id:              "s1",
+		Name:            "Team folder",
+		MimeType:        driveShortcutMimeType,
+		ShortcutDetails: &drive.FileShortcutDetails{TargetId: "f1", TargetMimeType: driveFolderMimeType},
+	})
+	for _, want := range []string{"[Folder] Team folder", "Shortcut to: f1", "Type: " + driveFolderMimeType} {
+		if !strings.Contains(got, want) {
+			t.Errorf("formatDriveListEntry() missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+// ============================================================================
+// Drive Query Builder Tests
+// ============================================================================
+
+// TestQuoteDriveQueryValue verifies escaping of Drive query string literals
+func TestQuoteDriveQueryValue(t *testing.T) {
+	testCases := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"plain", "report", `'report'`},
+		{"apostrophe", "Bob's notes", `'Bob\'s notes'`},
+		{"backslash", `C:\tmp`, `'C:\\tmp'`},
+		{"injection attempt", "x' or name contains '", `'x\' or name contains \''`},
+		{"escaped quote input", `\'`, `'\\\''`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := quoteDriveQueryValue(tc.value); got != tc.want {
+				t.Errorf("quoteDriveQueryValue(%q) = %s, want %s", tc.value, got, tc.want)
+			}
+		})
+	}
+}
+
+// TestBuildSearchDriveQuery verifies that structured filters map to the
+// expected Drive query clauses
+func TestBuildSearchDriveQuery(t *testing.T) {
+	testCases := []struct {
+		name    string
+		input   SearchDriveFilesInput
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "no filters",
+			input: SearchDriveFilesInput{},
+			want:  "trashed = false",
+		},
+		{
+			name:  "name with apostrophe",
+			input: SearchDriveFilesInput{Query: "Q3 'final'"},
+			want:  `name contains 'Q3 \'final\'' and trashed = false`,
+		},
+		{
+			name: "all filters",
+			input: SearchDriveFilesInput{
+				Query:          "budget",
+				FullText:       "forecast",
+				MimeType:       "application/pdf",
+				Owner:          "alice@example.com",
+				ModifiedAfter:  "2024-01-01",
+				ModifiedBefore: "2024-06-30T12:00:00+02:00",
+				Starred:        true,
+				SharedWithMe:   true,
+				ParentID:       "folder123",
+			},
+			want: "name contains 'budget' and fullText contains 'forecast' and mimeType = 'application/pdf' and " +
+				"'alice@example.com' in owners and modifiedTime > '2024-01-01T00:00:00Z' and " +
+				"modifiedTime < '2024-06-30T10:00:00Z' and starred = true and sharedWithMe = true and " +
+				"'folder123' in parents and trashed = false",
+		},
+		{
+			name:  "app properties sorted by key",
+			input: SearchDriveFilesInput{AppProperties: map[string]string{"team": "ops", "project": "it's"}},
+			want:  `appProperties has { key='project' and value='it\'s' } and appProperties has { key='team' and value='ops' } and trashed = false`,
+		},
+		{
+			name:    "invalid modifiedAfter",
+			input:   SearchDriveFilesInput{ModifiedAfter: "last tuesday"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := buildSearchDriveQuery(tc.input)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("Expected error, got query %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("query = %q\nwant    %q", got, tc.want)
+			}
+		})
+	}
+}
+
+// TestDriveQueryProperty_ValuesStayInsideLiteral verifies that for any input,
+// the quoted literal contains no unescaped single quote, so user input cannot
+// terminate the literal and add clauses of its own.
+func TestDriveQueryProperty_ValuesStayInsideLiteral(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		value := rapid.String().Draw(t, "value")
+		quoted := quoteDriveQueryValue(value)
+
+		inner := quoted[1 : len(quoted)-1]
+		for i := 0; i < len(inner); i++ {
+			switch inner[i] {
+			case '\\':
+				i++
+			case '\'':
+				t.Fatalf("Unescaped quote at offset %d in %s", i, quoted)
+			}
+		}
+	})
+}
+
+// TestDriveQueryTimesAreUTC verifies that modified time filters are normalized to UTC
+func TestDriveQueryTimesAreUTC(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*60*60)
+	q := (&driveQuery{}).ModifiedAfter(time.Date(2024, 3, 1, 8, 0, 0, 0, loc))
+
+	if want := "modifiedTime > '2024-03-01T00:00:00Z'"; q.String() != want {
+		t.Errorf("query = %q, want %q", q.String(), want)
+	}
+}
+
+// symlinkedSandbox sets up a sandbox holding a "Reports" symlink that points
+// at a directory outside it, returning both directories.
+func symlinkedSandbox(t *testing.T) (string, string) {
+	t.Helper()
+	sandbox, outside := t.TempDir(), t.TempDir()
+	t.Setenv("MCP_SANDBOX_DIR", sandbox)
+	if err := os.Symlink(outside, filepath.Join(sandbox, "Reports")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	return sandbox, outside
+}
+
+// TestDriveDownloadStaysInSandbox verifies a symlinked subdirectory named
+// like a Drive folder cannot redirect a recursive download
+func TestDriveDownloadStaysInSandbox(t *testing.T) {
+	sandbox, outside := symlinkedSandbox(t)
+
+	d := &driveDownloader{written: map[string]bool{}}
+	if err := d.downloadFolder(&drive.File{Id: "f1", Name: "Reports", MimeType: driveFolderMimeType}, sandbox); err == nil {
+		t.Error("downloadFolder() into a symlink leaving the sandbox should fail")
+	}
+	if _, _, err := writeLocalFile(filepath.Join(sandbox, "Reports", "q1.csv"), strings.NewReader("a,b\n")); err == nil {
+		t.Error("writeLocalFile() through a symlink leaving the sandbox should fail")
+	}
+	if entries, _ := os.ReadDir(outside); len(entries) > 0 {
+		t.Errorf("files were written outside the sandbox: %v", entries)
+	}
+
+	if _, _, err := writeLocalFile(filepath.Join(sandbox, "Archive", "q1.csv"), strings.NewReader("a,b\n")); err != nil {
+		t.Errorf("writeLocalFile() inside the sandbox: %v", err)
+	}
+}
+
+// TestDriveSyncDownloadStaysInSandbox verifies a symlinked local directory,
+// which the sync plan treats as missing, cannot redirect a download
+func TestDriveSyncDownloadStaysInSandbox(t *testing.T) {
+	sandbox, outside := symlinkedSandbox(t)
+
+	local, err := listLocalSyncEntries(sandbox)
+	if err != nil {
+		t.Fatal(err)
+	}
+	remote := map[string]*syncEntry{
+		"Reports":        {Path: "Reports", IsDir: true},
+		"Reports/q1.csv": {Path: "Reports/q1.csv", Size: 4, MD5: "aaa", File: &drive.File{Id: "f1", MimeType: "text/csv"}},
+	}
+	plan, err := planSync(remote, local)
+	if err != nil {
+		t.Fatalf("planSync() error = %v", err)
+	}
+	if len(plan.Actions) != 2 {
+		t.Fatalf("planSync() actions = %d, want mkdir and create", len(plan.Actions))
+	}
+
+	s := &driveSyncer{root: sandbox}
+	for _, a := range plan.Actions {
+		if err := s.download(a); err == nil {
+			t.Errorf("download(%s %s) through a symlink leaving the sandbox should fail", a.Op, a.Path)
+		}
+	}
+	if entries, _ := os.ReadDir(outside); len(entries) > 0 {
+		t.Errorf("files were written outside the sandbox: %v", entries)
+	}
+}
@@ -0,0 +1,164 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.orx.me/mcp/google-workspace/internal/utils"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+)
+
+// GetDriveFolderTreeInput defines input for get_drive_folder_tree tool
+type GetDriveFolderTreeInput struct {
+	Email    string `json:"email" jsonschema:"Email address to access Drive"`
+	FolderID string `json:"folderId" jsonschema:"Folder or shared drive ID to list"`
+	MaxDepth int    `json:"maxDepth,omitempty" jsonschema:"Number of folder levels to descend into (default 3)"`
+	MaxItems int    `json:"maxItems,omitempty" jsonschema:"Maximum number of files and folders to include (default 1000)"`
+}
+
+// GetDriveFolderTreeOutput defines output for get_drive_folder_tree tool
+type GetDriveFolderTreeOutput struct {
+	Tree string `json:"tree" jsonschema:"Indented folder tree with types and sizes"`
+}
+
+// driveTreeNode is a file or folder in a listed Drive tree.
+type driveTreeNode struct {
+	file     *drive.File
+	children []*driveTreeNode
+	// expanded is false for folders below the depth limit.
+	expanded bool
+}
+
+// size returns the total size of the node's listed content.
+func (n *driveTreeNode) size() int64 {
+	total := n.file.Size
+	for _, c := range n.children {
+		total += c.size()
+	}
+	return total
+}
+
+// listDriveChildren calls fn for every non-trashed item directly inside folderID.
+func listDriveChildren(ctx context.Context, srv *drive.Service, folderID, fields string, fn func(*drive.File) error) error {
+	query := (&driveQuery{}).InParents(folderID).Trashed(false)
+	return srv.Files.List().
+		SupportsAllDrives(true).
+		IncludeItemsFromAllDrives(true).
+		Q(query.String()).
+		OrderBy("folder, name").
+		PageSize(100).
+		Fields(googleapi.Field("nextPageToken, files("+fields+")")).
+		Pages(ctx, func(page *drive.FileList) error {
+			for _, f := range page.Files {
+				if err := fn(f); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+}
+
+// driveTreeLister builds a depth-limited tree for one get_drive_folder_tree call.
+type driveTreeLister struct {
+	ctx       context.Context
+	srv       *drive.Service
+	maxDepth  int
+	maxItems  int
+	items     int
+	truncated bool
+}
+
+// expand lists the children of node, descending into folders up to maxDepth.
+func (l *driveTreeLister) expand(node *driveTreeNode, depth int) error {
+	node.expanded = true
+	err := listDriveChildren(l.ctx, l.srv, node.file.Id, "id, name, mimeType, size", func(f *drive.File) error {
+		if l.items >= l.maxItems {
+			l.truncated = true
+			return errStopPaging
+		}
+		l.items++
+		node.children = append(node.children, &driveTreeNode{file: f})
+		return nil
+	})
+	if err != nil && err != errStopPaging {
+		return fmt.Errorf("failed to list %s: %w", node.file.Name, err)
+	}
+
+	if depth >= l.maxDepth {
+		return nil
+	}
+	for _, child := range node.children {
+		if child.file.MimeType == driveFolderMimeType && !l.truncated {
+			if err := l.expand(child, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// renderDriveTree writes node's children as an indented list.
+func renderDriveTree(b *strings.Builder, node *driveTreeNode, indent string) {
+	for _, child := range node.children {
+		if child.file.MimeType == driveFolderMimeType {
+			if child.expanded {
+				b.WriteString(fmt.Sprintf("%s- %s/ (folder, %d item(s), %d bytes, ID: %s)\n", indent, child.file.Name, len(child.children), child.size(), child.file.Id))
+				renderDriveTree(b, child, indent+"  ")
+			} else {
+				b.WriteString(fmt.Sprintf("%s- %s/ (folder, not expanded, ID: %s)\n", indent, child.file.Name, child.file.Id))
+			}
+			continue
+		}
+		b.WriteString(fmt.Sprintf("%s- %s (%s", indent, child.file.Name, child.file.MimeType))
+		if child.file.Size > 0 {
+			b.WriteString(fmt.Sprintf(", %d bytes", child.file.Size))
+		}
+		b.WriteString(fmt.Sprintf(", ID: %s)\n", child.file.Id))
+	}
+}
+
+// GetDriveFolderTree handles the get_drive_folder_tree tool call
+func GetDriveFolderTree(ctx context.Context, req *mcp.CallToolRequest, input GetDriveFolderTreeInput) (*mcp.CallToolResult, GetDriveFolderTreeOutput, error) {
+	srv, err := utils.NewDriveClient(input.Email)
+	if err != nil {
+		return nil, GetDriveFolderTreeOutput{}, err
+	}
+
+	maxDepth := input.MaxDepth
+	if maxDepth == 0 {
+		maxDepth = 3
+	}
+	maxItems := input.MaxItems
+	if maxItems == 0 {
+		maxItems = 1000
+	}
+
+	folder, err := srv.Files.Get(input.FolderID).
+		SupportsAllDrives(true).
+		Fields("id, name, mimeType").
+		Do()
+	if err != nil {
+		return nil, GetDriveFolderTreeOutput{}, fmt.Errorf("failed to get folder info: %w", err)
+	}
+	if folder.MimeType != driveFolderMimeType {
+		return nil, GetDriveFolderTreeOutput{}, fmt.Errorf("%s (%s) is not a folder", folder.Name, folder.MimeType)
+	}
+
+	lister := &driveTreeLister{ctx: ctx, srv: srv, maxDepth: maxDepth, maxItems: maxItems}
+	root := &driveTreeNode{file: folder}
+	if err := lister.expand(root, 1); err != nil {
+		return nil, GetDriveFolderTreeOutput{}, err
+	}
+
+	var resp strings.Builder
+	resp.WriteString(fmt.Sprintf("%s/ (%d item(s) listed, %d bytes, depth limit %d)\n", folder.Name, lister.items, root.size(), maxDepth))
+	renderDriveTree(&resp, root, "  ")
+	if lister.truncated {
+		resp.WriteString(fmt.Sprintf("\nStopped after %d items; raise maxItems to list more.\n", maxItems))
+	}
+
+	return nil, GetDriveFolderTreeOutput{Tree: resp.String()}, nil
+}
@@ -0,0 +1,350 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.orx.me/mcp/google-workspace/internal/utils"
+	admin "google.golang.org/api/admin/directory/v1"
+	"google.golang.org/api/drive/v3"
+)
+
+// GetDriveStorageQuotaInput defines input for get_drive_storage_quota tool
+type GetDriveStorageQuotaInput struct {
+	Email    string `json:"email,omitempty" jsonschema:"User whose storage quota to report (required unless allUsers is set)"`
+	AllUsers bool   `json:"allUsers,omitempty" jsonschema:"Report every user in the directory domain, largest first, with totals"`
+	Domain   string `json:"domain,omitempty" jsonschema:"Directory domain whose users to report when allUsers is set"`
+	Refresh  bool   `json:"refresh,omitempty" jsonschema:"Ignore cached results and query Drive again"`
+}
+
+// GetDriveStorageQuotaOutput defines output for get_drive_storage_quota tool
+type GetDriveStorageQuotaOutput struct {
+	Report string `json:"report" jsonschema:"Storage quota usage report"`
+}
+
+// GetDriveUsageBreakdownInput defines input for get_drive_usage_breakdown tool
+type GetDriveUsageBreakdownInput struct {
+	Email    string `json:"email" jsonschema:"User whose Drive usage to break down"`
+	MaxFiles int64  `json:"maxFiles,omitempty" jsonschema:"Maximum number of files to inspect (default 10000)"`
+	Top      int    `json:"top,omitempty" jsonschema:"Number of entries to show per breakdown (default 10)"`
+	Refresh  bool   `json:"refresh,omitempty" jsonschema:"Ignore cached results and walk the drive again"`
+}
+
+// GetDriveUsageBreakdownOutput defines output for get_drive_usage_breakdown tool
+type GetDriveUsageBreakdownOutput struct {
+	Report string `json:"report" jsonschema:"File sizes aggregated by MIME type, owner and top-level folder"`
+}
+
+// driveUsageCacheTTL is how long storage reports are served from cache.
+const driveUsageCacheTTL = 15 * time.Minute
+
+// driveUsageCache holds rendered storage reports; walking large drives is slow.
+var driveUsageCache = newResultCache(driveUsageCacheTTL)
+
+// outsideMyDrive groups files that are not stored under the user's My Drive root.
+const outsideMyDrive = "(outside My Drive)"
+
+// driveUsage accumulates file sizes by MIME type, owner and top-level folder.
+// Only files the user owns count toward owned and total, since only those
+// use their quota.
+type driveUsage struct {
+	files    int
+	owned    int
+	total    int64
+	byType   map[string]int64
+	byOwner  map[string]int64
+	byFolder map[string]int64
+}
+
+// aggregateDriveUsage sums the sizes of files by MIME type, owner and the
+// top-level folder of My Drive (identified by rootID) they live under. folders
+// maps folder IDs to the folders seen while listing; parents missing from it,
+// such as folders owned by someone else, are fetched with lookup. Files
+// directly in My Drive count under "My Drive".
+func aggregateDriveUsage(files []*drive.File, folders map[string]*drive.File, rootID string, lookup func(id string) (*drive.File, error)) *driveUsage {
+	usage := &driveUsage{
+		byType:   map[string]int64{},
+		byOwner:  map[string]int64{},
+		byFolder: map[string]int64{},
+	}
+
+	topLevel := map[string]string{}
+	var topFolder func(id string, depth int) string
+	topFolder = func(id string, depth int) string {
+		if name, ok := topLevel[id]; ok {
+			return name
+		}
+		folder, ok := folders[id]
+		if !ok && lookup != nil {
+			if f, err := lookup(id); err == nil {
+				folder, ok = f, true
+			}
+		}
+		name := outsideMyDrive
+		switch {
+		case ok && depth < 100 && len(folder.Parents) > 0 && folder.Parents[0] == rootID:
+			name = folder.Name + "/"
+		case ok && depth < 100 && len(folder.Parents) > 0:
+			name = topFolder(folder.Parents[0], depth+1)
+		}
+		topLevel[id] = name
+		return name
+	}
+
+	for _, f := range files {
+		if f.MimeType == driveFolderMimeType {
+			continue
+		}
+		usage.files++
+		if f.OwnedByMe {
+			usage.owned++
+			usage.total += f.Size
+		}
+		usage.byType[f.MimeType] += f.Size
+
+		owner := "(no owner: shared drive)"
+		if len(f.Owners) > 0 {
+			owner = f.Owners[0].EmailAddress
+		}
+		usage.byOwner[owner] += f.Size
+
+		folder := outsideMyDrive
+		if len(f.Parents) > 0 {
+			if f.Parents[0] == rootID {
+				folder = "My Drive"
+			} else {
+				folder = topFolder(f.Parents[0], 0)
+			}
+		}
+		usage.byFolder[folder] += f.Size
+	}
+	return usage
+}
+
+// writeTopSizes writes the largest entries of sizes, biggest first.
+func writeTopSizes(b *strings.Builder, title string, sizes map[string]int64, top int) {
+	keys := make([]string, 0, len(sizes))
+	for k := range sizes {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if sizes[keys[i]] != sizes[keys[j]] {
+			return sizes[keys[i]] > sizes[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+
+	b.WriteString(fmt.Sprintf("\nBy %s:\n", title))
+	for i, k := range keys {
+		if i == top {
+			b.WriteString(fmt.Sprintf("  ... %d more\n", len(keys)-top))
+			break
+		}
+		b.WriteString(fmt.Sprintf("  - %s: %s\n", k, formatBytes(sizes[k])))
+	}
+}
+
+// formatBytes renders a byte count with a binary unit.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// cachedNote tells the caller a report came from cache.
+func cachedNote(created time.Time) string {
+	return fmt.Sprintf("\n(cached result from %s; set refresh to recompute)\n", created.UTC().Format(time.RFC3339))
+}
+
+// driveStorageQuota returns the About.storageQuota of email.
+func driveStorageQuota(email string) (*drive.AboutStorageQuota, error) {
+	srv, err := utils.NewDriveClient(email)
+	if err != nil {
+		return nil, err
+	}
+	about, err := srv.About.Get().Fields("storageQuota").Do()
+	if err != nil {
+		return nil, err
+	}
+	return about.StorageQuota, nil
+}
+
+// describeStorageQuota renders one user's quota on a single line.
+func describeStorageQuota(email string, q *drive.AboutStorageQuota) string {
+	limit := "unlimited"
+	if q.Limit > 0 {
+		limit = fmt.Sprintf("%s (%.1f%% used)", formatBytes(q.Limit), float64(q.Usage)*100/float64(q.Limit))
+	}
+	return fmt.Sprintf("- %s: %s used of %s; Drive %s, Drive trash %s\n",
+		email, formatBytes(q.Usage), limit, formatBytes(q.UsageInDrive), formatBytes(q.UsageInDriveTrash))
+}
+
+// GetDriveStorageQuota handles the get_drive_storage_quota tool call
+func GetDriveStorageQuota(ctx context.Context, req *mcp.CallToolRequest, input GetDriveStorageQuotaInput) (*mcp.CallToolResult, GetDriveStorageQuotaOutput, error) {
+	if input.AllUsers && input.Domain == "" {
+		return nil, GetDriveStorageQuotaOutput{}, fmt.Errorf("domain is required when allUsers is set")
+	}
+	if !input.AllUsers && input.Email == "" {
+		return nil, GetDriveStorageQuotaOutput{}, fmt.Errorf("email is required unless allUsers is set")
+	}
+
+	key := "quota|" + strings.ToLower(input.Email)
+	if input.AllUsers {
+		key = "quota-domain|" + strings.ToLower(input.Domain)
+	}
+	if !input.Refresh {
+		if report, created, ok := driveUsageCache.get(key); ok {
+			return nil, GetDriveStorageQuotaOutput{Report: report + cachedNote(created)}, nil
+		}
+	}
+
+	if !input.AllUsers {
+		q, err := driveStorageQuota(input.Email)
+		if err != nil {
+			return nil, GetDriveStorageQuotaOutput{}, fmt.Errorf("failed to get storage quota: %w", err)
+		}
+		report := "Storage quota:\n" + describeStorageQuota(input.Email, q)
+		driveUsageCache.set(key, report)
+		return nil, GetDriveStorageQuotaOutput{Report: report}, nil
+	}
+
+	client, err := utils.DefaultClient()
+	if err != nil {
+		return nil, GetDriveStorageQuotaOutput{}, err
+	}
+	var users []string
+	err = client.Users.List().Domain(input.Domain).Pages(ctx, func(page *admin.Users) error {
+		for _, user := range page.Users {
+			if !user.Suspended {
+				users = append(users, user.PrimaryEmail)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, GetDriveStorageQuotaOutput{}, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	type userQuota struct {
+		email string
+		quota *drive.AboutStorageQuota
+	}
+	var quotas []userQuota
+	var total, totalDrive, totalTrash int64
+	var userErrors strings.Builder
+	for i, email := range users {
+		notifyProgress(ctx, req, float64(i), float64(len(users)), "Reading quota of "+email)
+		q, err := driveStorageQuota(email)
+		if err != nil {
+			userErrors.WriteString(fmt.Sprintf("- %s: %v\n", email, err))
+			continue
+		}
+		quotas = append(quotas, userQuota{email, q})
+		total += q.Usage
+		totalDrive += q.UsageInDrive
+		totalTrash += q.UsageInDriveTrash
+	}
+	sort.Slice(quotas, func(i, j int) bool { return quotas[i].quota.Usage > quotas[j].quota.Usage })
+
+	var resp strings.Builder
+	resp.WriteString(fmt.Sprintf("Storage quota for %d user(s) in %s:\n", len(quotas), input.Domain))
+	resp.WriteString(fmt.Sprintf("Total: %s used; Drive %s, Drive trash %s\n\n", formatBytes(total), formatBytes(totalDrive), formatBytes(totalTrash)))
+	for _, uq := range quotas {
+		resp.WriteString(describeStorageQuota(uq.email, uq.quota))
+	}
+	if userErrors.Len() > 0 {
+		resp.WriteString("\nUsers that could not be read:\n")
+		resp.WriteString(userErrors.String())
+	}
+
+	report := resp.String()
+	driveUsageCache.set(key, report)
+	return nil, GetDriveStorageQuotaOutput{Report: report}, nil
+}
+
+// GetDriveUsageBreakdown handles the get_drive_usage_breakdown tool call
+func GetDriveUsageBreakdown(ctx context.Context, req *mcp.CallToolRequest, input GetDriveUsageBreakdownInput) (*mcp.CallToolResult, GetDriveUsageBreakdownOutput, error) {
+	maxFiles := input.MaxFiles
+	if maxFiles == 0 {
+		maxFiles = 10000
+	}
+	top := input.Top
+	if top == 0 {
+		top = 10
+	}
+
+	key := fmt.Sprintf("breakdown|%s|%d|%d", strings.ToLower(input.Email), maxFiles, top)
+	if !input.Refresh {
+		if report, created, ok := driveUsageCache.get(key); ok {
+			return nil, GetDriveUsageBreakdownOutput{Report: report + cachedNote(created)}, nil
+		}
+	}
+
+	srv, err := utils.NewDriveClient(input.Email)
+	if err != nil {
+		return nil, GetDriveUsageBreakdownOutput{}, err
+	}
+
+	root, err := srv.Files.Get("root").Fields("id").Context(ctx).Do()
+	if err != nil {
+		return nil, GetDriveUsageBreakdownOutput{}, fmt.Errorf("failed to get My Drive root: %w", err)
+	}
+
+	var files []*drive.File
+	folders := map[string]*drive.File{}
+	truncated := false
+	query := (&driveQuery{}).Trashed(false)
+	err = srv.Files.List().
+		Q(query.String()).
+		SupportsAllDrives(true).
+		IncludeItemsFromAllDrives(true).
+		PageSize(1000).
+		Fields("nextPageToken, files(id, name, mimeType, size, parents, ownedByMe, owners(emailAddress))").
+		Pages(ctx, func(page *drive.FileList) error {
+			for _, f := range page.Files {
+				if int64(len(files)) >= maxFiles {
+					truncated = true
+					return errStopPaging
+				}
+				files = append(files, f)
+				if f.MimeType == driveFolderMimeType {
+					folders[f.Id] = f
+				}
+			}
+			notifyProgress(ctx, req, float64(len(files)), float64(maxFiles), fmt.Sprintf("Listed %d files", len(files)))
+			return nil
+		})
+	if err != nil && err != errStopPaging {
+		return nil, GetDriveUsageBreakdownOutput{}, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	lookup := func(id string) (*drive.File, error) {
+		return srv.Files.Get(id).SupportsAllDrives(true).Fields("id, name, parents").Context(ctx).Do()
+	}
+	usage := aggregateDriveUsage(files, folders, root.Id, lookup)
+
+	var resp strings.Builder
+	resp.WriteString(fmt.Sprintf("Drive usage for %s: %d file(s), %d owned using %s of quota\n", input.Email, usage.files, usage.owned, formatBytes(usage.total)))
+	resp.WriteString("Breakdowns include files shared with the user. Google Docs, Sheets and Slides do not count toward quota and are shown as 0 B.\n")
+	writeTopSizes(&resp, "MIME type", usage.byType, top)
+	writeTopSizes(&resp, "owner", usage.byOwner, top)
+	writeTopSizes(&resp, "top-level folder", usage.byFolder, top)
+	if truncated {
+		resp.WriteString(fmt.Sprintf("\nStopped after %d files; raise maxFiles for a complete breakdown.\n", maxFiles))
+	}
+
+	report := resp.String()
+	driveUsageCache.set(key, report)
+	return nil, GetDriveUsageBreakdownOutput{Report: report}, nil
+}
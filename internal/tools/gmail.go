@@ -3,9 +3,11 @@ package tools
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"go.orx.me/mcp/google-workspace/internal/utils"
+	"google.golang.org/api/gmail/v1"
 )
 
 // ListGmailInput defines input for list_gmail tool
@@ -30,25 +32,26 @@ func ListGmail(ctx context.Context, req *mcp.CallToolRequest, input ListGmailInp
 		return nil, ListGmailOutput{}, err
 	}
 
-	var resp string
-	for _, msg := range messages.Messages {
-		fullMsg, err := srv.Users.Messages.Get("me", msg.Id).Do()
-		if err != nil {
+	ids := make([]string, len(messages.Messages))
+	for i, msg := range messages.Messages {
+		ids[i] = msg.Id
+	}
+
+	var resp strings.Builder
+	results := fetchGmailMetadata(ctx, srv, ids)
+	for _, r := range results {
+		if r.Err != nil {
 			continue
 		}
-
-		var subject string
-		for _, header := range fullMsg.Payload.Headers {
-			if header.Name == "Subject" {
-				subject = header.Value
-				break
-			}
+		var headers []*gmail.MessagePartHeader
+		if r.Message.Payload != nil {
+			headers = r.Message.Payload.Headers
 		}
-
-		resp += fmt.Sprintf("ID: %s, Subject: %s\n", msg.Id, subject)
+		resp.WriteString(fmt.Sprintf("ID: %s, Subject: %s\n", r.ID, gmailHeader(headers, "Subject")))
 	}
+	writeGmailFetchFailures(&resp, results)
 
-	return nil, ListGmailOutput{Messages: resp}, nil
+	return nil, ListGmailOutput{Messages: resp.String()}, nil
 }
 
 // RegisterGmailTools registers all Gmail-related tools with the MCP server
@@ -57,4 +60,111 @@ func RegisterGmailTools(server *mcp.Server) {
 		Name:        "list_gmail",
 		Description: "List Gmail Messages",
 	}, ListGmail)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "search_gmail",
+		Description: "Search Gmail messages with a Gmail query, label filters and pagination",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, SearchGmail)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_gmail_message",
+		Description: "Read a Gmail message: headers, body text (HTML converted to text when there is no plain part) and attachments",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, GetGmailMessage)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_gmail_attachment",
+		Description: "Get a Gmail attachment: return its content (text extracted when possible), save it to a sandboxed directory or upload it into a Drive folder",
+	}, GetGmailAttachment)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_gmail_thread",
+		Description: "Read a Gmail conversation: participants and every message in order with quoted replies and signatures stripped",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, GetGmailThread)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_gmail_labels",
+		Description: "List Gmail system labels and the nested tree of user labels with colors and, optionally, message counts",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, ListGmailLabels)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_gmail_filters",
+		Description: "List Gmail filters with their criteria and actions",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, ListGmailFilters)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_gmail_forwarding",
+		Description: "Show a mailbox's auto-forwarding setting and forwarding addresses",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, GetGmailForwarding)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "audit_gmail_forwarding",
+		Description: "Find mailboxes, for one user or every user in a directory domain, that auto-forward or filter-forward mail to external addresses",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, AuditGmailForwarding)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_vacation_settings",
+		Description: "Show a mailbox's vacation responder: whether it is on, its schedule, who gets replies, subject and message",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, GetVacationSettings)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_gmail_send_as",
+		Description: "List a mailbox's send-as addresses with display name, verification status and signature",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, ListGmailSendAs)
+
+	if gmailSendEnabled() {
+		mcp.AddTool(server, &mcp.Tool{
+			Name:        "send_gmail",
+			Description: "Send an email with To/Cc/Bcc recipients, plain text and HTML bodies and attachments from the sandbox, base64 content or Drive",
+			Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(false)},
+		}, SendGmail)
+
+		mcp.AddTool(server, &mcp.Tool{
+			Name:        "reply_gmail",
+			Description: "Reply (or reply all) to a Gmail message in its thread, quoting the original",
+			Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(false)},
+		}, ReplyGmail)
+
+		mcp.AddTool(server, &mcp.Tool{
+			Name:        "forward_gmail",
+			Description: "Forward a Gmail message with its original attachments, optionally with a note",
+			Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(false)},
+		}, ForwardGmail)
+	}
+
+	if gmailDraftsEnabled() {
+		registerGmailDraftTools(server)
+	}
+
+	if gmailModifyEnabled() {
+		registerGmailLabelTools(server)
+		registerGmailCleanupTools(server)
+	}
+
+	if gmailDeleteEnabled() {
+		mcp.AddTool(server, &mcp.Tool{
+			Name:        "delete_gmail_messages",
+			Description: "Permanently delete Gmail messages and threads, skipping the trash (requires confirm)",
+			Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(true), IdempotentHint: true},
+		}, DeleteGmailMessages)
+	}
+
+	if gmailSettingsEnabled() {
+		registerGmailFilterTools(server)
+		registerGmailForwardingTools(server)
+		registerGmailSendAsTools(server)
+
+		mcp.AddTool(server, &mcp.Tool{
+			Name:        "set_vacation_settings",
+			Description: "Turn the Gmail vacation responder on or off with a schedule, subject, plain or HTML message and audience, optionally creating a matching Calendar out-of-office event",
+		}, SetVacationSettings)
+	}
 }
@@ -0,0 +1,267 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.orx.me/mcp/google-workspace/internal/utils"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/googleapi"
+)
+
+// GetGmailAttachmentInput defines input for get_gmail_attachment tool
+type GetGmailAttachmentInput struct {
+	Email         string `json:"email" jsonschema:"Email address to access Gmail (and Drive when saving there)"`
+	MessageID     string `json:"messageId" jsonschema:"Message ID the attachment belongs to"`
+	Attachment    string `json:"attachment,omitempty" jsonschema:"Attachment ID, part ID or file name from get_gmail_message (default: the only attachment, or every attachment when saving)"`
+	Destination   string `json:"destination,omitempty" jsonschema:"Local directory inside the sandbox to save into instead of returning the content"`
+	DriveFolderID string `json:"driveFolderId,omitempty" jsonschema:"Drive folder ID to upload into instead of returning the content"`
+	Overwrite     bool   `json:"overwrite,omitempty" jsonschema:"Replace local files that already exist (default false)"`
+}
+
+// GetGmailAttachmentOutput defines output for get_gmail_attachment tool
+type GetGmailAttachmentOutput struct {
+	Result string `json:"result" jsonschema:"Attachment content, or where each attachment was saved"`
+}
+
+// selectGmailAttachments returns the attachments matching key, which may be an
+// attachment ID, a part ID or a file name. An empty key selects every
+// attachment.
+func selectGmailAttachments(attachments []gmailAttachment, key string) ([]gmailAttachment, error) {
+	if len(attachments) == 0 {
+		return nil, fmt.Errorf("message has no attachments")
+	}
+	if key == "" {
+		return attachments, nil
+	}
+	for _, a := range attachments {
+		if a.AttachmentID == key || a.PartID == key {
+			return []gmailAttachment{a}, nil
+		}
+	}
+	var matches []gmailAttachment
+	for _, a := range attachments {
+		if strings.EqualFold(a.Filename, key) {
+			matches = append(matches, a)
+		}
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("attachment %q not found", key)
+	}
+	return matches, nil
+}
+
+// gmailAttachmentName returns the attachment's file name, falling back to a
+// name derived from its part ID.
+func gmailAttachmentName(a gmailAttachment) string {
+	return localFileName(a.Filename, "attachment-"+a.PartID)
+}
+
+// formatGmailAttachmentContent renders attachment data for the model: text is
+// returned as is (HTML converted to text) and anything else base64 encoded.
+func formatGmailAttachmentContent(a gmailAttachment, data []byte) (string, error) {
+	if len(data) > maxInlineContentSize {
+		return "", fmt.Errorf("attachment is larger than %d bytes; set destination or driveFolderId to save it", maxInlineContentSize)
+	}
+
+	header := fmt.Sprintf("Attachment: %s\n  MIME type: %s\n  Size: %d bytes\n", gmailAttachmentName(a), a.MimeType, len(data))
+	mediaType := strings.ToLower(strings.TrimSpace(strings.SplitN(a.MimeType, ";", 2)[0]))
+	switch {
+	case mediaType == "text/html":
+		return header + "\nContent (converted from HTML):\n" + htmlToText(string(data)), nil
+	case isTextMimeType(mediaType) && utf8.Valid(data):
+		return header + "\nContent:\n" + string(data), nil
+	}
+	return header + "\nContent (base64):\n" + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// gmailAttachmentFiler saves attachments of one message locally or to Drive.
+type gmailAttachmentFiler struct {
+	ctx       context.Context
+	gmail     *gmail.Service
+	drive     *drive.Service
+	messageID string
+	subject   string
+	from      string
+	overwrite bool
+	report    strings.Builder
+	// written holds the local paths saved by this call, so attachments that
+	// share a name do not replace or skip one another.
+	written map[string]bool
+}
+
+// fetch downloads an attachment and checks it against the size Gmail reported.
+func (f *gmailAttachmentFiler) fetch(a gmailAttachment) ([]byte, error) {
+	data, err := fetchGmailAttachmentData(f.ctx, f.gmail, f.messageID, a)
+	if err != nil {
+		return nil, err
+	}
+	if a.Size > 0 && int64(len(data)) != a.Size {
+		return nil, fmt.Errorf("attachment %s is %d bytes, expected %d", gmailAttachmentName(a), len(data), a.Size)
+	}
+	return data, nil
+}
+
+// saveLocal writes an attachment into dir. A later attachment with the same
+// name as an earlier one gets its part ID added to the name.
+func (f *gmailAttachmentFiler) saveLocal(a gmailAttachment, dir string) error {
+	if f.written == nil {
+		f.written = map[string]bool{}
+	}
+	target := uniqueLocalPath(f.written, dir, gmailAttachmentName(a), a.PartID)
+	if !f.overwrite {
+		if _, err := os.Stat(target); err == nil {
+			f.report.WriteString(fmt.Sprintf("Skipped: %s (already exists)\n", target))
+			return nil
+		}
+	}
+
+	data, err := f.fetch(a)
+	if err != nil {
+		return err
+	}
+	size, md5sum, err := writeLocalFile(target, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", target, err)
+	}
+	f.report.WriteString(fmt.Sprintf("Saved: %s (%d bytes, md5 %s)\n", target, size, md5sum))
+	return nil
+}
+
+// saveDrive uploads an attachment into folderID. The file records the message
+// and part it came from in its app properties, so filing the same message
+// again finds the earlier upload instead of creating a duplicate.
+func (f *gmailAttachmentFiler) saveDrive(a gmailAttachment, folderID string) error {
+	q := (&driveQuery{}).
+		InParents(folderID).
+		AppProperty("gmailMessageId", f.messageID).
+		AppProperty("gmailPartId", a.PartID).
+		Trashed(false)
+	existing, err := f.drive.Files.List().
+		Q(q.String()).
+		SupportsAllDrives(true).
+		IncludeItemsFromAllDrives(true).
+		Fields("files(id, name, webViewLink)").
+		Context(f.ctx).
+		Do()
+	if err != nil {
+		return fmt.Errorf("failed to check for an earlier upload: %w", err)
+	}
+	if len(existing.Files) > 0 {
+		file := existing.Files[0]
+		f.report.WriteString(fmt.Sprintf("Already in Drive: %s (ID: %s, %s)\n", file.Name, file.Id, file.WebViewLink))
+		return nil
+	}
+
+	data, err := f.fetch(a)
+	if err != nil {
+		return err
+	}
+	file := &drive.File{
+		Name:        gmailAttachmentName(a),
+		Parents:     []string{folderID},
+		Description: fmt.Sprintf("Attachment of %q from %s", f.subject, f.from),
+		AppProperties: map[string]string{
+			"gmailMessageId": f.messageID,
+			"gmailPartId":    a.PartID,
+		},
+	}
+	var opts []googleapi.MediaOption
+	if a.MimeType != "" {
+		opts = append(opts, googleapi.ContentType(a.MimeType))
+	}
+	created, err := f.drive.Files.Create(file).
+		Media(bytes.NewReader(data), opts...).
+		SupportsAllDrives(true).
+		Fields("id, name, size, md5Checksum, webViewLink").
+		Context(f.ctx).
+		Do()
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", file.Name, err)
+	}
+	f.report.WriteString(fmt.Sprintf("Uploaded: %s (ID: %s, %d bytes, %s)\n", created.Name, created.Id, created.Size, created.WebViewLink))
+	return nil
+}
+
+// GetGmailAttachment handles the get_gmail_attachment tool call
+func GetGmailAttachment(ctx context.Context, req *mcp.CallToolRequest, input GetGmailAttachmentInput) (*mcp.CallToolResult, GetGmailAttachmentOutput, error) {
+	if input.Destination != "" && input.DriveFolderID != "" {
+		return nil, GetGmailAttachmentOutput{}, fmt.Errorf("set either destination or driveFolderId, not both")
+	}
+
+	srv, err := utils.NewGmailClient(input.Email)
+	if err != nil {
+		return nil, GetGmailAttachmentOutput{}, err
+	}
+
+	// Attachment IDs are not stable across requests, so the message is read
+	// again to find the attachment and get a current ID for it.
+	msg, err := srv.Users.Messages.Get("me", input.MessageID).
+		Format("full").
+		Context(ctx).
+		Do()
+	if err != nil {
+		return nil, GetGmailAttachmentOutput{}, fmt.Errorf("failed to get message: %w", err)
+	}
+	parsed, err := parseGmailMessage(msg.Payload)
+	if err != nil {
+		return nil, GetGmailAttachmentOutput{}, err
+	}
+	selected, err := selectGmailAttachments(parsed.Attachments, input.Attachment)
+	if err != nil {
+		return nil, GetGmailAttachmentOutput{}, err
+	}
+
+	f := &gmailAttachmentFiler{
+		ctx:       ctx,
+		gmail:     srv,
+		messageID: msg.Id,
+		subject:   gmailHeader(parsed.Headers, "Subject"),
+		from:      gmailHeader(parsed.Headers, "From"),
+		overwrite: input.Overwrite,
+	}
+
+	switch {
+	case input.Destination != "":
+		dir, err := utils.SandboxPath(input.Destination)
+		if err != nil {
+			return nil, GetGmailAttachmentOutput{}, err
+		}
+		for _, a := range selected {
+			if err := f.saveLocal(a, dir); err != nil {
+				return nil, GetGmailAttachmentOutput{}, err
+			}
+		}
+	case input.DriveFolderID != "":
+		if f.drive, err = utils.NewDriveClient(input.Email); err != nil {
+			return nil, GetGmailAttachmentOutput{}, err
+		}
+		for _, a := range selected {
+			if err := f.saveDrive(a, input.DriveFolderID); err != nil {
+				return nil, GetGmailAttachmentOutput{}, err
+			}
+		}
+	default:
+		if len(selected) > 1 {
+			return nil, GetGmailAttachmentOutput{}, fmt.Errorf("message has %d matching attachments; set attachment to pick one or set destination or driveFolderId to save them all", len(selected))
+		}
+		data, err := f.fetch(selected[0])
+		if err != nil {
+			return nil, GetGmailAttachmentOutput{}, err
+		}
+		content, err := formatGmailAttachmentContent(selected[0], data)
+		if err != nil {
+			return nil, GetGmailAttachmentOutput{}, err
+		}
+		return nil, GetGmailAttachmentOutput{Result: content}, nil
+	}
+
+	return nil, GetGmailAttachmentOutput{Result: f.report.String()}, nil
+}
@@ -0,0 +1,359 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.orx.me/mcp/google-workspace/internal/utils"
+	"google.golang.org/api/gmail/v1"
+)
+
+// TrashGmailMessagesInput defines input for trash_gmail_messages tool
+type TrashGmailMessagesInput struct {
+	Email      string   `json:"email" jsonschema:"Email address to access Gmail"`
+	MessageIDs []string `json:"messageIds,omitempty" jsonschema:"Message IDs to move to the trash"`
+	ThreadIDs  []string `json:"threadIds,omitempty" jsonschema:"Thread IDs to move to the trash with all their messages"`
+}
+
+// TrashGmailMessagesOutput defines output for trash_gmail_messages tool
+type TrashGmailMessagesOutput struct {
+	Result string `json:"result" jsonschema:"Number of messages and threads trashed and any failures"`
+}
+
+// UntrashGmailMessagesInput defines input for untrash_gmail_messages tool
+type UntrashGmailMessagesInput struct {
+	Email      string   `json:"email" jsonschema:"Email address to access Gmail"`
+	MessageIDs []string `json:"messageIds,omitempty" jsonschema:"Message IDs to restore from the trash"`
+	ThreadIDs  []string `json:"threadIds,omitempty" jsonschema:"Thread IDs to restore from the trash with all their messages"`
+}
+
+// UntrashGmailMessagesOutput defines output for untrash_gmail_messages tool
+type UntrashGmailMessagesOutput struct {
+	Result string `json:"result" jsonschema:"Number of messages and threads restored and any failures"`
+}
+
+// DeleteGmailMessagesInput defines input for delete_gmail_messages tool
+type DeleteGmailMessagesInput struct {
+	Email      string   `json:"email" jsonschema:"Email address to access Gmail"`
+	MessageIDs []string `json:"messageIds,omitempty" jsonschema:"Message IDs to delete permanently"`
+	ThreadIDs  []string `json:"threadIds,omitempty" jsonschema:"Thread IDs to delete permanently with all their messages"`
+	Confirm    bool     `json:"confirm" jsonschema:"Must be true; the messages skip the trash and cannot be recovered"`
+}
+
+// DeleteGmailMessagesOutput defines output for delete_gmail_messages tool
+type DeleteGmailMessagesOutput struct {
+	Result string `json:"result" jsonschema:"Number of messages and threads deleted and any failures"`
+}
+
+// CleanupGmailInput defines input for cleanup_gmail tool
+type CleanupGmailInput struct {
+	Email       string `json:"email" jsonschema:"Email address to access Gmail"`
+	Query       string `json:"query" jsonschema:"Gmail search query selecting the messages, e.g. category:promotions older_than:1y"`
+	Action      string `json:"action" jsonschema:"What to do with every matching message: trash, untrash or delete (permanent)"`
+	DryRun      bool   `json:"dryRun" jsonschema:"When true only count the matching messages and show a sample; nothing is changed"`
+	MaxMessages int    `json:"maxMessages,omitempty" jsonschema:"Maximum number of messages to act on (default 5000, max 50000)"`
+	Confirm     bool   `json:"confirm,omitempty" jsonschema:"Must be true when action is delete"`
+}
+
+// CleanupGmailOutput defines output for cleanup_gmail tool
+type CleanupGmailOutput struct {
+	Result string `json:"result" jsonschema:"Matching message count, or the number of messages changed and any failures"`
+}
+
+// gmailDeleteScopes is the only scope that allows deleting mail permanently.
+var gmailDeleteScopes = []string{gmail.MailGoogleComScope}
+
+// gmailDeleteEnabled reports whether GMAIL_ENABLE_DELETE turns on permanent
+// deletion, which needs full mailbox access.
+func gmailDeleteEnabled() bool {
+	return envFlag("GMAIL_ENABLE_DELETE")
+}
+
+// gmailBulkResult counts what a trash, untrash or delete run changed.
+type gmailBulkResult struct {
+	Messages int
+	Threads  int
+	Failures []string
+}
+
+// gmailBulkRun applies one action to messages and threads, reporting progress
+// against the total number of IDs it was given.
+type gmailBulkRun struct {
+	ctx    context.Context
+	req    *mcp.CallToolRequest
+	srv    *gmail.Service
+	action string
+	done   int
+	total  int
+	result gmailBulkResult
+}
+
+// checkGmailBulkAction reports whether action is one gmailBulkRun can apply.
+func checkGmailBulkAction(action string) error {
+	switch action {
+	case "trash", "untrash", "delete":
+		return nil
+	}
+	return fmt.Errorf("invalid action %q: expected trash, untrash or delete", action)
+}
+
+// newGmailBulkRun validates action and creates a client with the scope it needs.
+func newGmailBulkRun(ctx context.Context, req *mcp.CallToolRequest, email, action string, total int) (*gmailBulkRun, error) {
+	if err := checkGmailBulkAction(action); err != nil {
+		return nil, err
+	}
+	scopes := gmailModifyScopes
+	if action == "delete" {
+		if !gmailDeleteEnabled() {
+			return nil, fmt.Errorf("permanent deletion is disabled; set GMAIL_ENABLE_DELETE to allow it")
+		}
+		scopes = gmailDeleteScopes
+	}
+
+	srv, err := utils.NewGmailClient(email, scopes...)
+	if err != nil {
+		return nil, err
+	}
+	return &gmailBulkRun{ctx: ctx, req: req, srv: srv, action: action, total: total}, nil
+}
+
+// messages applies the action to ids. Permanent deletion uses batchDelete,
+// up to gmailBatchModifyLimit IDs per request; trash and untrash have no
+// batch endpoint and run on a bounded worker pool instead.
+func (r *gmailBulkRun) messages(ids []string) {
+	for start := 0; start < len(ids); start += gmailBatchModifyLimit {
+		end := min(start+gmailBatchModifyLimit, len(ids))
+		chunk := ids[start:end]
+
+		if r.action == "delete" {
+			err := r.srv.Users.Messages.BatchDelete("me", &gmail.BatchDeleteMessagesRequest{Ids: chunk}).Context(r.ctx).Do()
+			if err != nil {
+				r.result.Failures = append(r.result.Failures, fmt.Sprintf("- messages %d-%d: %v", start+1, end, err))
+			} else {
+				r.result.Messages += len(chunk)
+			}
+		} else {
+			results := fetchGmailMessages(r.ctx, chunk, gmailFetchConcurrency, func(ctx context.Context, id string) (*gmail.Message, error) {
+				if r.action == "trash" {
+					return r.srv.Users.Messages.Trash("me", id).Fields("id").Context(ctx).Do()
+				}
+				return r.srv.Users.Messages.Untrash("me", id).Fields("id").Context(ctx).Do()
+			})
+			for _, res := range results {
+				if res.Err != nil {
+					r.result.Failures = append(r.result.Failures, fmt.Sprintf("- message %s: %v", res.ID, res.Err))
+				} else {
+					r.result.Messages++
+				}
+			}
+		}
+
+		r.done += len(chunk)
+		notifyProgress(r.ctx, r.req, float64(r.done), float64(r.total), fmt.Sprintf("Processed %d of %d", r.done, r.total))
+	}
+}
+
+// threads applies the action to each thread in turn.
+func (r *gmailBulkRun) threads(ids []string) {
+	for _, id := range ids {
+		var err error
+		switch r.action {
+		case "trash":
+			_, err = r.srv.Users.Threads.Trash("me", id).Fields("id").Context(r.ctx).Do()
+		case "untrash":
+			_, err = r.srv.Users.Threads.Untrash("me", id).Fields("id").Context(r.ctx).Do()
+		case "delete":
+			err = r.srv.Users.Threads.Delete("me", id).Context(r.ctx).Do()
+		}
+		if err != nil {
+			r.result.Failures = append(r.result.Failures, fmt.Sprintf("- thread %s: %v", id, err))
+		} else {
+			r.result.Threads++
+		}
+		r.done++
+		notifyProgress(r.ctx, r.req, float64(r.done), float64(r.total), "Processed thread "+id)
+	}
+}
+
+// report summarizes the run; verb describes the action, e.g. "Trashed".
+func (r *gmailBulkRun) report(verb string) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%s %d messages and %d threads\n", verb, r.result.Messages, r.result.Threads))
+	if len(r.result.Failures) > 0 {
+		b.WriteString(fmt.Sprintf("\nFailures (%d):\n", len(r.result.Failures)))
+		b.WriteString(strings.Join(r.result.Failures, "\n"))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// runGmailBulkAction applies action to the given messages and threads.
+func runGmailBulkAction(ctx context.Context, req *mcp.CallToolRequest, email, action string, messageIDs, threadIDs []string) (*gmailBulkRun, error) {
+	if len(messageIDs) == 0 && len(threadIDs) == 0 {
+		return nil, fmt.Errorf("set messageIds or threadIds")
+	}
+	run, err := newGmailBulkRun(ctx, req, email, action, len(messageIDs)+len(threadIDs))
+	if err != nil {
+		return nil, err
+	}
+	run.messages(messageIDs)
+	run.threads(threadIDs)
+	return run, nil
+}
+
+// listGmailMessageIDs returns the IDs of up to limit messages matching query,
+// reporting progress as pages are listed.
+func listGmailMessageIDs(ctx context.Context, req *mcp.CallToolRequest, srv *gmail.Service, query string, includeSpamTrash bool, limit int) ([]string, bool, error) {
+	var ids []string
+	more := false
+	err := srv.Users.Messages.List("me").
+		Q(query).
+		IncludeSpamTrash(includeSpamTrash).
+		MaxResults(500).
+		Fields("nextPageToken, messages/id").
+		Pages(ctx, func(page *gmail.ListMessagesResponse) error {
+			for _, m := range page.Messages {
+				if len(ids) == limit {
+					more = true
+					return errStopPaging
+				}
+				ids = append(ids, m.Id)
+			}
+			notifyProgress(ctx, req, float64(len(ids)), float64(limit), fmt.Sprintf("Listed %d messages", len(ids)))
+			return nil
+		})
+	if err != nil && err != errStopPaging {
+		return nil, false, err
+	}
+	return ids, more, nil
+}
+
+// TrashGmailMessages handles the trash_gmail_messages tool call
+func TrashGmailMessages(ctx context.Context, req *mcp.CallToolRequest, input TrashGmailMessagesInput) (*mcp.CallToolResult, TrashGmailMessagesOutput, error) {
+	run, err := runGmailBulkAction(ctx, req, input.Email, "trash", input.MessageIDs, input.ThreadIDs)
+	if err != nil {
+		return nil, TrashGmailMessagesOutput{}, err
+	}
+	return nil, TrashGmailMessagesOutput{Result: run.report("Moved to trash:")}, nil
+}
+
+// UntrashGmailMessages handles the untrash_gmail_messages tool call
+func UntrashGmailMessages(ctx context.Context, req *mcp.CallToolRequest, input UntrashGmailMessagesInput) (*mcp.CallToolResult, UntrashGmailMessagesOutput, error) {
+	run, err := runGmailBulkAction(ctx, req, input.Email, "untrash", input.MessageIDs, input.ThreadIDs)
+	if err != nil {
+		return nil, UntrashGmailMessagesOutput{}, err
+	}
+	return nil, UntrashGmailMessagesOutput{Result: run.report("Restored from trash:")}, nil
+}
+
+// DeleteGmailMessages handles the delete_gmail_messages tool call
+func DeleteGmailMessages(ctx context.Context, req *mcp.CallToolRequest, input DeleteGmailMessagesInput) (*mcp.CallToolResult, DeleteGmailMessagesOutput, error) {
+	if !input.Confirm {
+		return nil, DeleteGmailMessagesOutput{}, fmt.Errorf("permanent deletion requires confirm to be true; use trash_gmail_messages for a recoverable delete")
+	}
+	run, err := runGmailBulkAction(ctx, req, input.Email, "delete", input.MessageIDs, input.ThreadIDs)
+	if err != nil {
+		return nil, DeleteGmailMessagesOutput{}, err
+	}
+	return nil, DeleteGmailMessagesOutput{Result: run.report("Deleted permanently:")}, nil
+}
+
+// CleanupGmail handles the cleanup_gmail tool call. Every matching ID is
+// listed before anything changes, so trashing messages cannot shift later
+// pages of the search and make it skip matches.
+func CleanupGmail(ctx context.Context, req *mcp.CallToolRequest, input CleanupGmailInput) (*mcp.CallToolResult, CleanupGmailOutput, error) {
+	if strings.TrimSpace(input.Query) == "" {
+		return nil, CleanupGmailOutput{}, fmt.Errorf("query is required; cleanup never applies to the whole mailbox")
+	}
+	if input.Action == "delete" && !input.DryRun && !input.Confirm {
+		return nil, CleanupGmailOutput{}, fmt.Errorf("permanent deletion requires confirm to be true; run with dryRun first to see how many messages match")
+	}
+	if err := checkGmailBulkAction(input.Action); err != nil {
+		return nil, CleanupGmailOutput{}, err
+	}
+	maxMessages := input.MaxMessages
+	if maxMessages < 0 {
+		return nil, CleanupGmailOutput{}, fmt.Errorf("maxMessages cannot be negative")
+	}
+	if maxMessages == 0 {
+		maxMessages = 5000
+	}
+	if maxMessages > 50000 {
+		return nil, CleanupGmailOutput{}, fmt.Errorf("maxMessages cannot exceed 50000")
+	}
+
+	// Untrash must look in the trash; trash and delete may still pick up spam.
+	includeSpamTrash := input.Action != "trash"
+
+	// A dry run only reads, so it needs neither GMAIL_ENABLE_DELETE nor the
+	// full mail scope.
+	var run *gmailBulkRun
+	var srv *gmail.Service
+	var err error
+	if input.DryRun {
+		srv, err = utils.NewGmailClient(input.Email)
+	} else if run, err = newGmailBulkRun(ctx, req, input.Email, input.Action, 0); err == nil {
+		srv = run.srv
+	}
+	if err != nil {
+		return nil, CleanupGmailOutput{}, err
+	}
+
+	ids, more, err := listGmailMessageIDs(ctx, req, srv, input.Query, includeSpamTrash, maxMessages)
+	if err != nil {
+		return nil, CleanupGmailOutput{}, fmt.Errorf("failed to search messages: %w", err)
+	}
+	limitNote := ""
+	if more {
+		limitNote = fmt.Sprintf(" (stopped at maxMessages=%d; more messages match)", maxMessages)
+	}
+
+	if input.DryRun {
+		var resp strings.Builder
+		resp.WriteString(fmt.Sprintf("Dry run: %d messages match %q and would be affected by %s%s\n", len(ids), input.Query, input.Action, limitNote))
+		sample := ids[:min(len(ids), 5)]
+		if len(sample) > 0 {
+			resp.WriteString("\nSample:\n")
+			results := fetchGmailMetadata(ctx, srv, sample)
+			for _, r := range results {
+				if r.Err == nil {
+					resp.WriteString(formatGmailSummary(r.Message, nil))
+				}
+			}
+			writeGmailFetchFailures(&resp, results)
+		}
+		return nil, CleanupGmailOutput{Result: resp.String()}, nil
+	}
+
+	if len(ids) == 0 {
+		return nil, CleanupGmailOutput{Result: fmt.Sprintf("No messages match %q", input.Query)}, nil
+	}
+	run.total = len(ids)
+	run.messages(ids)
+
+	verbs := map[string]string{"trash": "Moved to trash:", "untrash": "Restored from trash:", "delete": "Deleted permanently:"}
+	return nil, CleanupGmailOutput{Result: run.report(verbs[input.Action]) + limitNote}, nil
+}
+
+// registerGmailCleanupTools registers the trash and cleanup tools.
+func registerGmailCleanupTools(server *mcp.Server) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "trash_gmail_messages",
+		Description: "Move Gmail messages and threads to the trash",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(true), IdempotentHint: true},
+	}, TrashGmailMessages)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "untrash_gmail_messages",
+		Description: "Restore Gmail messages and threads from the trash",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(false), IdempotentHint: true},
+	}, UntrashGmailMessages)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "cleanup_gmail",
+		Description: "Trash, untrash or permanently delete every message matching a Gmail search query; use dryRun to count matches first",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(true)},
+	}, CleanupGmail)
+}
@@ -0,0 +1,517 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/http"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"go.orx.me/mcp/google-workspace/internal/utils"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/gmail/v1"
+)
+
+// GmailAttachmentInput describes one attachment of an outgoing message. Exactly
+// one of filePath, content and driveFileId must be set.
+type GmailAttachmentInput struct {
+	FilePath    string `json:"filePath,omitempty" jsonschema:"Local file inside the sandbox to attach"`
+	Content     string `json:"content,omitempty" jsonschema:"Base64-encoded attachment content"`
+	DriveFileID string `json:"driveFileId,omitempty" jsonschema:"Drive file ID to attach; Google Docs/Sheets/Slides are attached in the matching Office format"`
+	Filename    string `json:"filename,omitempty" jsonschema:"Attachment file name (default: the file's own name; required with content)"`
+	MimeType    string `json:"mimeType,omitempty" jsonschema:"Attachment MIME type (default: detected from the file name or content)"`
+}
+
+// gmailMaxAttachmentSize is the total attachment size Gmail accepts on one
+// message.
+const gmailMaxAttachmentSize = 25 << 20
+
+// gmailOutgoingAttachment is a file attached to a composed message. Parts
+// with a ContentID are sent inline so HTML bodies can refer to them as cid:
+// URLs.
+type gmailOutgoingAttachment struct {
+	Filename  string
+	MimeType  string
+	ContentID string
+	Data      []byte
+}
+
+// gmailComposition is an outgoing message. build renders it as RFC 5322 text
+// ready for Messages.Send or Drafts.Create. ThreadID is not part of the text;
+// callers pass it alongside so Gmail files the message in that thread. Drafts
+// may be saved before any recipient is known.
+type gmailComposition struct {
+	ThreadID    string
+	Draft       bool
+	From        string
+	To          []string
+	Cc          []string
+	Bcc         []string
+	Subject     string
+	Text        string
+	HTML        string
+	InReplyTo   string
+	References  string
+	Date        time.Time
+	Attachments []gmailOutgoingAttachment
+}
+
+// mimeEntity is a MIME header block and its already encoded body.
+type mimeEntity struct {
+	header textproto.MIMEHeader
+	body   []byte
+}
+
+// formatAddressList parses addrs, each of which may itself be a comma
+// separated list, and renders them for an address header. Display names
+// outside ASCII are encoded per RFC 2047.
+func formatAddressList(field string, addrs []string) (string, int, error) {
+	var formatted []string
+	for _, a := range addrs {
+		if strings.TrimSpace(a) == "" {
+			continue
+		}
+		list, err := mail.ParseAddressList(a)
+		if err != nil {
+			return "", 0, fmt.Errorf("invalid %s address %q: %w", field, a, err)
+		}
+		for _, addr := range list {
+			formatted = append(formatted, addr.String())
+		}
+	}
+	return strings.Join(formatted, ", "), len(formatted), nil
+}
+
+// checkHeaderValue rejects values that would break out of their header line.
+func checkHeaderValue(field, v string) error {
+	if strings.ContainsAny(v, "\r\n") {
+		return fmt.Errorf("%s must not contain line breaks", field)
+	}
+	return nil
+}
+
+// textEntity returns a UTF-8 text part encoded as quoted-printable.
+func textEntity(mediaType, content string) mimeEntity {
+	var buf bytes.Buffer
+	qp := quotedprintable.NewWriter(&buf)
+	qp.Write([]byte(content))
+	qp.Close()
+
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Type", mime.FormatMediaType(mediaType, map[string]string{"charset": "utf-8"}))
+	h.Set("Content-Transfer-Encoding", "quoted-printable")
+	return mimeEntity{header: h, body: buf.Bytes()}
+}
+
+// attachmentEntity returns a base64 encoded attachment part. The file name is
+// encoded per RFC 2231 when it is not plain ASCII.
+func attachmentEntity(a gmailOutgoingAttachment) mimeEntity {
+	contentType := mime.FormatMediaType(a.MimeType, map[string]string{"name": a.Filename})
+	if contentType == "" {
+		contentType = mime.FormatMediaType("application/octet-stream", map[string]string{"name": a.Filename})
+	}
+
+	disposition := "attachment"
+	h := textproto.MIMEHeader{}
+	if a.ContentID != "" && !strings.ContainsAny(a.ContentID, "\r\n<>") {
+		disposition = "inline"
+		h.Set("Content-ID", "<"+a.ContentID+">")
+	}
+	h.Set("Content-Type", contentType)
+	h.Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": a.Filename}))
+	h.Set("Content-Transfer-Encoding", "base64")
+
+	encoded := base64.StdEncoding.EncodeToString(a.Data)
+	var buf bytes.Buffer
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76])
+		buf.WriteString("\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded)
+	return mimeEntity{header: h, body: buf.Bytes()}
+}
+
+// multipartEntity wraps parts in a multipart/subtype entity.
+func multipartEntity(subtype string, parts ...mimeEntity) mimeEntity {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	for _, p := range parts {
+		w, _ := mw.CreatePart(p.header)
+		w.Write(p.body)
+	}
+	mw.Close()
+
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Type", mime.FormatMediaType("multipart/"+subtype, map[string]string{"boundary": mw.Boundary()}))
+	return mimeEntity{header: h, body: buf.Bytes()}
+}
+
+// content returns the message body: the text and HTML alternatives followed by
+// any attachments. When only HTML is given its text rendering is used as the
+// plain alternative so clients without HTML support still show something.
+func (c *gmailComposition) content() mimeEntity {
+	text := c.Text
+	if text == "" && c.HTML != "" {
+		text = htmlToText(c.HTML)
+	}
+
+	body := textEntity("text/plain", text)
+	if c.HTML != "" {
+		body = multipartEntity("alternative", body, textEntity("text/html", c.HTML))
+	}
+	if len(c.Attachments) == 0 {
+		return body
+	}
+
+	parts := []mimeEntity{body}
+	for _, a := range c.Attachments {
+		parts = append(parts, attachmentEntity(a))
+	}
+	return multipartEntity("mixed", parts...)
+}
+
+// build renders the message. Bcc is kept in the headers; Gmail delivers to
+// those recipients and strips the header before the message goes out.
+func (c *gmailComposition) build() ([]byte, error) {
+	var b bytes.Buffer
+	writeHeader := func(name, value string) {
+		if value != "" {
+			b.WriteString(name + ": " + value + "\r\n")
+		}
+	}
+
+	recipients := 0
+	addressHeaders := []struct {
+		name  string
+		addrs []string
+	}{
+		{"From", []string{c.From}},
+		{"To", c.To},
+		{"Cc", c.Cc},
+		{"Bcc", c.Bcc},
+	}
+	formatted := make([]string, len(addressHeaders))
+	for i, h := range addressHeaders {
+		v, n, err := formatAddressList(strings.ToLower(h.name), h.addrs)
+		if err != nil {
+			return nil, err
+		}
+		formatted[i] = v
+		if h.name != "From" {
+			recipients += n
+		}
+	}
+	if recipients == 0 && !c.Draft {
+		return nil, fmt.Errorf("at least one to, cc or bcc recipient is required")
+	}
+	for _, v := range []struct{ field, value string }{
+		{"subject", c.Subject},
+		{"inReplyTo", c.InReplyTo},
+		{"references", c.References},
+	} {
+		if err := checkHeaderValue(v.field, v.value); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, h := range addressHeaders {
+		writeHeader(h.name, formatted[i])
+	}
+	writeHeader("Subject", mime.QEncoding.Encode("utf-8", c.Subject))
+	if !c.Date.IsZero() {
+		writeHeader("Date", c.Date.Format(time.RFC1123Z))
+	}
+	writeHeader("In-Reply-To", c.InReplyTo)
+	writeHeader("References", c.References)
+	writeHeader("MIME-Version", "1.0")
+
+	content := c.content()
+	keys := make([]string, 0, len(content.header))
+	for k := range content.header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		writeHeader(k, content.header.Get(k))
+	}
+	b.WriteString("\r\n")
+	b.Write(content.body)
+
+	return b.Bytes(), nil
+}
+
+// gmailReplyHeaders are the headers of the original message needed to
+// address and thread a reply.
+var gmailReplyHeaders = []string{"From", "Reply-To", "To", "Cc", "Date", "Subject", "Message-ID", "References"}
+
+// getGmailReplyTarget fetches the message being replied to with the headers
+// setReply needs.
+func getGmailReplyTarget(ctx context.Context, srv *gmail.Service, messageID string) (*gmail.Message, error) {
+	msg, err := srv.Users.Messages.Get("me", messageID).
+		Format("metadata").
+		MetadataHeaders(gmailReplyHeaders...).
+		Fields("id, threadId, payload/headers").
+		Context(ctx).
+		Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message being replied to: %w", err)
+	}
+	return msg, nil
+}
+
+// gmailReplySubject prefixes subject with "Re: " unless it already has one.
+func gmailReplySubject(subject string) string {
+	if len(subject) >= 3 && strings.EqualFold(subject[:3], "re:") {
+		return subject
+	}
+	return "Re: " + subject
+}
+
+// gmailForwardSubject prefixes subject with "Fwd: " unless it already has a
+// forward prefix.
+func gmailForwardSubject(subject string) string {
+	lower := strings.ToLower(subject)
+	if strings.HasPrefix(lower, "fwd:") || strings.HasPrefix(lower, "fw:") {
+		return subject
+	}
+	return "Fwd: " + subject
+}
+
+// originalHeaders returns the headers of a fetched message.
+func originalHeaders(original *gmail.Message) []*gmail.MessagePartHeader {
+	if original.Payload == nil {
+		return nil
+	}
+	return original.Payload.Headers
+}
+
+// setThread places c in original's thread. In-Reply-To and References are
+// set as well so mail clients other than Gmail thread the message too.
+func (c *gmailComposition) setThread(original *gmail.Message) {
+	headers := originalHeaders(original)
+	msgID := strings.TrimSpace(gmailHeader(headers, "Message-ID"))
+	refs := strings.Fields(gmailHeader(headers, "References"))
+	if msgID != "" {
+		refs = append(refs, msgID)
+	}
+
+	c.ThreadID = original.ThreadId
+	c.InReplyTo = msgID
+	c.References = strings.Join(refs, " ")
+}
+
+// setReply threads c as a reply to original. An empty subject becomes
+// "Re: <original subject>" and, when no recipient is given, the reply goes to
+// whoever gmailReplyRecipients picks.
+func (c *gmailComposition) setReply(original *gmail.Message) {
+	c.setThread(original)
+	headers := originalHeaders(original)
+	if c.Subject == "" {
+		c.Subject = gmailReplySubject(gmailHeader(headers, "Subject"))
+	}
+	if len(c.To) == 0 && len(c.Cc) == 0 && len(c.Bcc) == 0 {
+		c.To, _ = gmailReplyRecipients(headers, c.From, false)
+	}
+}
+
+// gmailReplyRecipients works out who a reply to a message with the given
+// headers goes to. A reply goes to the Reply-To or From address, unless self
+// sent the original, in which case it goes back to the original recipients.
+// Reply-all also copies everyone else on the original. self never appears in
+// the result and each address appears once.
+func gmailReplyRecipients(headers []*gmail.MessagePartHeader, self string, replyAll bool) (to, cc []string) {
+	seen := map[string]bool{}
+	if addr, err := mail.ParseAddress(self); err == nil {
+		seen[strings.ToLower(addr.Address)] = true
+	}
+	add := func(list *[]string, header string) {
+		addrs, err := mail.ParseAddressList(header)
+		if err != nil {
+			return
+		}
+		for _, a := range addrs {
+			key := strings.ToLower(a.Address)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			*list = append(*list, a.String())
+		}
+	}
+
+	from := gmailHeader(headers, "From")
+	fromSelf := false
+	if addr, err := mail.ParseAddress(from); err == nil {
+		fromSelf = seen[strings.ToLower(addr.Address)]
+	}
+
+	if fromSelf {
+		add(&to, gmailHeader(headers, "To"))
+		if replyAll {
+			add(&cc, gmailHeader(headers, "Cc"))
+		}
+		return to, cc
+	}
+
+	if replyTo := gmailHeader(headers, "Reply-To"); replyTo != "" {
+		add(&to, replyTo)
+	} else {
+		add(&to, from)
+	}
+	if replyAll {
+		add(&cc, gmailHeader(headers, "To"))
+		add(&cc, gmailHeader(headers, "Cc"))
+	}
+	return to, cc
+}
+
+// loadGmailAttachments resolves attachment inputs to their content. A Drive
+// client for email is only created when an attachment comes from Drive.
+func loadGmailAttachments(ctx context.Context, email string, inputs []GmailAttachmentInput) ([]gmailOutgoingAttachment, error) {
+	var driveSrv *drive.Service
+	attachments := make([]gmailOutgoingAttachment, 0, len(inputs))
+	remaining := int64(gmailMaxAttachmentSize)
+
+	for i, in := range inputs {
+		sources := 0
+		for _, v := range []string{in.FilePath, in.Content, in.DriveFileID} {
+			if v != "" {
+				sources++
+			}
+		}
+		if sources != 1 {
+			return nil, fmt.Errorf("attachment %d: set exactly one of filePath, content or driveFileId", i+1)
+		}
+
+		var att gmailOutgoingAttachment
+		var err error
+		switch {
+		case in.FilePath != "":
+			att, err = readLocalAttachment(in.FilePath, remaining)
+		case in.Content != "":
+			att, err = decodeInlineAttachment(in)
+		default:
+			if driveSrv == nil {
+				if driveSrv, err = utils.NewDriveClient(email); err != nil {
+					return nil, err
+				}
+			}
+			att, err = fetchDriveAttachment(ctx, driveSrv, in.DriveFileID, remaining)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("attachment %d: %w", i+1, err)
+		}
+
+		if in.Filename != "" {
+			att.Filename = in.Filename
+		}
+		if in.MimeType != "" {
+			att.MimeType = in.MimeType
+		}
+		if att.MimeType == "" {
+			att.MimeType = mime.TypeByExtension(filepath.Ext(att.Filename))
+		}
+		if att.MimeType == "" {
+			att.MimeType = http.DetectContentType(att.Data)
+		}
+
+		remaining -= int64(len(att.Data))
+		if remaining < 0 {
+			return nil, fmt.Errorf("attachments exceed Gmail's %d byte limit", gmailMaxAttachmentSize)
+		}
+		attachments = append(attachments, att)
+	}
+	return attachments, nil
+}
+
+// readLocalAttachment reads a sandboxed file of at most limit bytes.
+func readLocalAttachment(p string, limit int64) (gmailOutgoingAttachment, error) {
+	path, err := utils.SandboxPath(p)
+	if err != nil {
+		return gmailOutgoingAttachment{}, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return gmailOutgoingAttachment{}, fmt.Errorf("failed to read local file: %w", err)
+	}
+	if info.IsDir() {
+		return gmailOutgoingAttachment{}, fmt.Errorf("%s is a directory", p)
+	}
+	if info.Size() > limit {
+		return gmailOutgoingAttachment{}, fmt.Errorf("attachments exceed Gmail's %d byte limit", gmailMaxAttachmentSize)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return gmailOutgoingAttachment{}, fmt.Errorf("failed to read local file: %w", err)
+	}
+	return gmailOutgoingAttachment{Filename: filepath.Base(path), Data: data}, nil
+}
+
+// decodeInlineAttachment decodes base64 content given directly in the call.
+func decodeInlineAttachment(in GmailAttachmentInput) (gmailOutgoingAttachment, error) {
+	if in.Filename == "" {
+		return gmailOutgoingAttachment{}, fmt.Errorf("filename is required with content")
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(in.Content), ""))
+	if err != nil {
+		return gmailOutgoingAttachment{}, fmt.Errorf("invalid base64 content: %w", err)
+	}
+	return gmailOutgoingAttachment{Filename: in.Filename, Data: data}, nil
+}
+
+// fetchDriveAttachment downloads a Drive file of at most limit bytes,
+// exporting Google-native files in their default export format.
+func fetchDriveAttachment(ctx context.Context, srv *drive.Service, fileID string, limit int64) (gmailOutgoingAttachment, error) {
+	file, err := srv.Files.Get(fileID).
+		SupportsAllDrives(true).
+		Fields("id, name, mimeType, size").
+		Context(ctx).
+		Do()
+	if err != nil {
+		return gmailOutgoingAttachment{}, fmt.Errorf("failed to get Drive file: %w", err)
+	}
+
+	att := gmailOutgoingAttachment{Filename: file.Name, MimeType: file.MimeType}
+	var resp *http.Response
+	if isGoogleNativeMimeType(file.MimeType) {
+		exportMime := defaultExportMimeTypes[file.MimeType]
+		if exportMime == "" {
+			return gmailOutgoingAttachment{}, fmt.Errorf("%s (%s) cannot be attached", file.Name, file.MimeType)
+		}
+		if ext := exportExtensions[exportMime]; ext != "" && !strings.HasSuffix(strings.ToLower(att.Filename), ext) {
+			att.Filename += ext
+		}
+		att.MimeType = exportMime
+		resp, err = srv.Files.Export(file.Id, exportMime).Context(ctx).Download()
+	} else {
+		if file.Size > limit {
+			return gmailOutgoingAttachment{}, fmt.Errorf("attachments exceed Gmail's %d byte limit", gmailMaxAttachmentSize)
+		}
+		resp, err = srv.Files.Get(file.Id).SupportsAllDrives(true).Context(ctx).Download()
+	}
+	if err != nil {
+		return gmailOutgoingAttachment{}, fmt.Errorf("failed to download Drive file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	att.Data, err = io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return gmailOutgoingAttachment{}, fmt.Errorf("failed to download Drive file: %w", err)
+	}
+	if int64(len(att.Data)) > limit {
+		return gmailOutgoingAttachment{}, fmt.Errorf("attachments exceed Gmail's %d byte limit", gmailMaxAttachmentSize)
+	}
+	return att, nil
+}
@@ -0,0 +1,397 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.orx.me/mcp/google-workspace/internal/utils"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/googleapi"
+)
+
+// CreateGmailDraftInput defines input for create_gmail_draft tool
+type CreateGmailDraftInput struct {
+	Email            string                 `json:"email" jsonschema:"Email address whose mailbox holds the draft"`
+	To               []string               `json:"to,omitempty" jsonschema:"Recipient addresses (default for replies: the original sender)"`
+	Cc               []string               `json:"cc,omitempty" jsonschema:"Cc recipient addresses"`
+	Bcc              []string               `json:"bcc,omitempty" jsonschema:"Bcc recipient addresses"`
+	Subject          string                 `json:"subject,omitempty" jsonschema:"Message subject (default for replies: Re: <original subject>)"`
+	Body             string                 `json:"body,omitempty" jsonschema:"Plain text body"`
+	HTMLBody         string                 `json:"htmlBody,omitempty" jsonschema:"HTML body, sent as an alternative to the plain text body (derived from the HTML when body is empty)"`
+	Attachments      []GmailAttachmentInput `json:"attachments,omitempty" jsonschema:"Files to attach from the sandbox, inline base64 content or Drive"`
+	ReplyToMessageID string                 `json:"replyToMessageId,omitempty" jsonschema:"Message ID this draft replies to; the draft joins its thread"`
+}
+
+// CreateGmailDraftOutput defines output for create_gmail_draft tool
+type CreateGmailDraftOutput struct {
+	Result string `json:"result" jsonschema:"Created draft ID, message ID and thread ID"`
+}
+
+// ListGmailDraftsInput defines input for list_gmail_drafts tool
+type ListGmailDraftsInput struct {
+	Email      string `json:"email" jsonschema:"Email address to access Gmail"`
+	Query      string `json:"query,omitempty" jsonschema:"Gmail search query to filter drafts, e.g. to:alice@example.com"`
+	MaxResults int64  `json:"maxResults,omitempty" jsonschema:"Maximum number of drafts to return (default 20, max 500)"`
+	PageToken  string `json:"pageToken,omitempty" jsonschema:"Page token from a previous list_gmail_drafts call to fetch the next page"`
+}
+
+// ListGmailDraftsOutput defines output for list_gmail_drafts tool
+type ListGmailDraftsOutput struct {
+	Drafts string `json:"drafts" jsonschema:"Drafts with recipients, subject and snippet"`
+}
+
+// GetGmailDraftInput defines input for get_gmail_draft tool
+type GetGmailDraftInput struct {
+	Email          string `json:"email" jsonschema:"Email address to access Gmail"`
+	DraftID        string `json:"draftId" jsonschema:"Draft ID (from list_gmail_drafts or create_gmail_draft)"`
+	IncludeHeaders bool   `json:"includeHeaders,omitempty" jsonschema:"Include every raw message header in the result"`
+}
+
+// GetGmailDraftOutput defines output for get_gmail_draft tool
+type GetGmailDraftOutput struct {
+	Draft string `json:"draft" jsonschema:"Draft headers, body text and attachments"`
+}
+
+// UpdateGmailDraftInput defines input for update_gmail_draft tool
+type UpdateGmailDraftInput struct {
+	Email       string                 `json:"email" jsonschema:"Email address whose mailbox holds the draft"`
+	DraftID     string                 `json:"draftId" jsonschema:"Draft ID to update"`
+	To          []string               `json:"to,omitempty" jsonschema:"Recipient addresses"`
+	Cc          []string               `json:"cc,omitempty" jsonschema:"Cc recipient addresses"`
+	Bcc         []string               `json:"bcc,omitempty" jsonschema:"Bcc recipient addresses"`
+	Subject     string                 `json:"subject,omitempty" jsonschema:"Message subject"`
+	Body        string                 `json:"body,omitempty" jsonschema:"Plain text body"`
+	HTMLBody    string                 `json:"htmlBody,omitempty" jsonschema:"HTML body, sent as an alternative to the plain text body (derived from the HTML when body is empty)"`
+	Attachments []GmailAttachmentInput `json:"attachments,omitempty" jsonschema:"Files to attach from the sandbox, inline base64 content or Drive"`
+}
+
+// UpdateGmailDraftOutput defines output for update_gmail_draft tool
+type UpdateGmailDraftOutput struct {
+	Result string `json:"result" jsonschema:"Updated draft ID, message ID and thread ID"`
+}
+
+// DeleteGmailDraftInput defines input for delete_gmail_draft tool
+type DeleteGmailDraftInput struct {
+	Email   string `json:"email" jsonschema:"Email address to access Gmail"`
+	DraftID string `json:"draftId" jsonschema:"Draft ID to delete"`
+	Confirm bool   `json:"confirm" jsonschema:"Must be true; the draft skips the trash and cannot be recovered"`
+}
+
+// DeleteGmailDraftOutput defines output for delete_gmail_draft tool
+type DeleteGmailDraftOutput struct {
+	Result string `json:"result" jsonschema:"Deletion result"`
+}
+
+// SendGmailDraftInput defines input for send_gmail_draft tool
+type SendGmailDraftInput struct {
+	Email   string `json:"email" jsonschema:"Email address whose mailbox holds the draft"`
+	DraftID string `json:"draftId" jsonschema:"Draft ID to send"`
+}
+
+// SendGmailDraftOutput defines output for send_gmail_draft tool
+type SendGmailDraftOutput struct {
+	Result string `json:"result" jsonschema:"Sent message ID and thread ID"`
+}
+
+// gmailDraftScopes lets draft tools manage drafts and read the message a
+// draft replies to. Neither scope can send mail without a draft.
+var gmailDraftScopes = []string{gmail.GmailComposeScope, gmail.GmailReadonlyScope}
+
+// gmailDraftsEnabled reports whether GMAIL_ENABLE_DRAFTS turns on the draft
+// tools, which need the gmail.compose scope.
+func gmailDraftsEnabled() bool {
+	return envFlag("GMAIL_ENABLE_DRAFTS")
+}
+
+// newGmailDraft renders c as the content of a draft create or update call.
+func newGmailDraft(c *gmailComposition) (*gmail.Draft, []byte, error) {
+	c.Draft = true
+	raw, err := c.build()
+	if err != nil {
+		return nil, nil, err
+	}
+	return &gmail.Draft{Message: &gmail.Message{ThreadId: c.ThreadID}}, raw, nil
+}
+
+// formatGmailDraftResult describes a draft returned by a create or update call.
+func formatGmailDraftResult(action string, draft *gmail.Draft) string {
+	msg := draft.Message
+	if msg == nil {
+		msg = &gmail.Message{}
+	}
+	return fmt.Sprintf("Draft %s successfully:\n  Draft ID: %s\n  Message ID: %s\n  Thread ID: %s",
+		action, draft.Id, msg.Id, msg.ThreadId)
+}
+
+// CreateGmailDraft handles the create_gmail_draft tool call
+func CreateGmailDraft(ctx context.Context, req *mcp.CallToolRequest, input CreateGmailDraftInput) (*mcp.CallToolResult, CreateGmailDraftOutput, error) {
+	srv, err := utils.NewGmailClient(input.Email, gmailDraftScopes...)
+	if err != nil {
+		return nil, CreateGmailDraftOutput{}, err
+	}
+
+	attachments, err := loadGmailAttachments(ctx, input.Email, input.Attachments)
+	if err != nil {
+		return nil, CreateGmailDraftOutput{}, err
+	}
+
+	c := &gmailComposition{
+		From:        input.Email,
+		To:          input.To,
+		Cc:          input.Cc,
+		Bcc:         input.Bcc,
+		Subject:     input.Subject,
+		Text:        input.Body,
+		HTML:        input.HTMLBody,
+		Date:        time.Now(),
+		Attachments: attachments,
+	}
+	if input.ReplyToMessageID != "" {
+		original, err := getGmailReplyTarget(ctx, srv, input.ReplyToMessageID)
+		if err != nil {
+			return nil, CreateGmailDraftOutput{}, err
+		}
+		c.setReply(original)
+	}
+
+	draft, raw, err := newGmailDraft(c)
+	if err != nil {
+		return nil, CreateGmailDraftOutput{}, err
+	}
+	created, err := srv.Users.Drafts.Create("me", draft).
+		Media(bytes.NewReader(raw), googleapi.ContentType("message/rfc822")).
+		Context(ctx).
+		Do()
+	if err != nil {
+		return nil, CreateGmailDraftOutput{}, fmt.Errorf("failed to create draft: %w", err)
+	}
+
+	return nil, CreateGmailDraftOutput{Result: formatGmailDraftResult("created", created)}, nil
+}
+
+// ListGmailDrafts handles the list_gmail_drafts tool call
+func ListGmailDrafts(ctx context.Context, req *mcp.CallToolRequest, input ListGmailDraftsInput) (*mcp.CallToolResult, ListGmailDraftsOutput, error) {
+	maxResults := input.MaxResults
+	if maxResults == 0 {
+		maxResults = 20
+	}
+	if maxResults > 500 {
+		return nil, ListGmailDraftsOutput{}, fmt.Errorf("maxResults cannot exceed 500")
+	}
+
+	srv, err := utils.NewGmailClient(input.Email, gmailDraftScopes...)
+	if err != nil {
+		return nil, ListGmailDraftsOutput{}, err
+	}
+
+	call := srv.Users.Drafts.List("me").MaxResults(maxResults).Context(ctx)
+	if input.Query != "" {
+		call = call.Q(input.Query)
+	}
+	if input.PageToken != "" {
+		call = call.PageToken(input.PageToken)
+	}
+	list, err := call.Do()
+	if err != nil {
+		return nil, ListGmailDraftsOutput{}, fmt.Errorf("failed to list drafts: %w", err)
+	}
+
+	if len(list.Drafts) == 0 {
+		return nil, ListGmailDraftsOutput{Drafts: "No drafts found."}, nil
+	}
+
+	ids := make([]string, len(list.Drafts))
+	for i, d := range list.Drafts {
+		ids[i] = d.Id
+	}
+	results := fetchGmailMessages(ctx, ids, gmailFetchConcurrency, func(ctx context.Context, id string) (*gmail.Message, error) {
+		draft, err := srv.Users.Drafts.Get("me", id).
+			Format("metadata").
+			Fields("id, message(id, threadId, labelIds, snippet, payload/headers)").
+			Context(ctx).
+			Do()
+		if err != nil {
+			return nil, err
+		}
+		return draft.Message, nil
+	})
+
+	var resp strings.Builder
+	resp.WriteString(fmt.Sprintf("Drafts (%d):\n", len(results)))
+	for _, r := range results {
+		if r.Err == nil && r.Message != nil {
+			resp.WriteString(fmt.Sprintf("Draft ID: %s\n", r.ID))
+			resp.WriteString(formatGmailSummary(r.Message, nil))
+		}
+	}
+	writeGmailFetchFailures(&resp, results)
+	if list.NextPageToken != "" {
+		resp.WriteString(fmt.Sprintf("\nNext page token: %s\n", list.NextPageToken))
+	}
+
+	return nil, ListGmailDraftsOutput{Drafts: resp.String()}, nil
+}
+
+// GetGmailDraft handles the get_gmail_draft tool call
+func GetGmailDraft(ctx context.Context, req *mcp.CallToolRequest, input GetGmailDraftInput) (*mcp.CallToolResult, GetGmailDraftOutput, error) {
+	srv, err := utils.NewGmailClient(input.Email, gmailDraftScopes...)
+	if err != nil {
+		return nil, GetGmailDraftOutput{}, err
+	}
+
+	draft, err := srv.Users.Drafts.Get("me", input.DraftID).
+		Format("full").
+		Context(ctx).
+		Do()
+	if err != nil {
+		return nil, GetGmailDraftOutput{}, fmt.Errorf("failed to get draft: %w", err)
+	}
+	if draft.Message == nil {
+		return nil, GetGmailDraftOutput{}, fmt.Errorf("draft %s has no message", input.DraftID)
+	}
+
+	msg, err := formatGmailMessage(draft.Message, input.IncludeHeaders)
+	if err != nil {
+		return nil, GetGmailDraftOutput{}, err
+	}
+
+	return nil, GetGmailDraftOutput{Draft: fmt.Sprintf("Draft %s\n%s", draft.Id, msg)}, nil
+}
+
+// UpdateGmailDraft handles the update_gmail_draft tool call. The draft's
+// content is replaced, but a reply draft stays in its thread: the existing
+// thread ID, In-Reply-To and References are carried over.
+func UpdateGmailDraft(ctx context.Context, req *mcp.CallToolRequest, input UpdateGmailDraftInput) (*mcp.CallToolResult, UpdateGmailDraftOutput, error) {
+	srv, err := utils.NewGmailClient(input.Email, gmailDraftScopes...)
+	if err != nil {
+		return nil, UpdateGmailDraftOutput{}, err
+	}
+
+	existing, err := srv.Users.Drafts.Get("me", input.DraftID).
+		Format("metadata").
+		Fields("id, message(threadId, payload/headers)").
+		Context(ctx).
+		Do()
+	if err != nil {
+		return nil, UpdateGmailDraftOutput{}, fmt.Errorf("failed to get draft: %w", err)
+	}
+
+	attachments, err := loadGmailAttachments(ctx, input.Email, input.Attachments)
+	if err != nil {
+		return nil, UpdateGmailDraftOutput{}, err
+	}
+
+	c := &gmailComposition{
+		From:        input.Email,
+		To:          input.To,
+		Cc:          input.Cc,
+		Bcc:         input.Bcc,
+		Subject:     input.Subject,
+		Text:        input.Body,
+		HTML:        input.HTMLBody,
+		Date:        time.Now(),
+		Attachments: attachments,
+	}
+	if msg := existing.Message; msg != nil {
+		c.ThreadID = msg.ThreadId
+		if msg.Payload != nil {
+			c.InReplyTo = gmailHeader(msg.Payload.Headers, "In-Reply-To")
+			c.References = strings.Join(strings.Fields(gmailHeader(msg.Payload.Headers, "References")), " ")
+		}
+	}
+
+	draft, raw, err := newGmailDraft(c)
+	if err != nil {
+		return nil, UpdateGmailDraftOutput{}, err
+	}
+	draft.Id = input.DraftID
+	updated, err := srv.Users.Drafts.Update("me", input.DraftID, draft).
+		Media(bytes.NewReader(raw), googleapi.ContentType("message/rfc822")).
+		Context(ctx).
+		Do()
+	if err != nil {
+		return nil, UpdateGmailDraftOutput{}, fmt.Errorf("failed to update draft: %w", err)
+	}
+
+	return nil, UpdateGmailDraftOutput{Result: formatGmailDraftResult("updated", updated)}, nil
+}
+
+// DeleteGmailDraft handles the delete_gmail_draft tool call
+func DeleteGmailDraft(ctx context.Context, req *mcp.CallToolRequest, input DeleteGmailDraftInput) (*mcp.CallToolResult, DeleteGmailDraftOutput, error) {
+	if !input.Confirm {
+		return nil, DeleteGmailDraftOutput{}, fmt.Errorf("deleting a draft requires confirm to be true; drafts are deleted permanently")
+	}
+
+	srv, err := utils.NewGmailClient(input.Email, gmailDraftScopes...)
+	if err != nil {
+		return nil, DeleteGmailDraftOutput{}, err
+	}
+
+	if err := srv.Users.Drafts.Delete("me", input.DraftID).Context(ctx).Do(); err != nil {
+		return nil, DeleteGmailDraftOutput{}, fmt.Errorf("failed to delete draft: %w", err)
+	}
+
+	return nil, DeleteGmailDraftOutput{Result: fmt.Sprintf("Draft %s deleted permanently", input.DraftID)}, nil
+}
+
+// SendGmailDraft handles the send_gmail_draft tool call
+func SendGmailDraft(ctx context.Context, req *mcp.CallToolRequest, input SendGmailDraftInput) (*mcp.CallToolResult, SendGmailDraftOutput, error) {
+	srv, err := utils.NewGmailClient(input.Email, gmailDraftScopes...)
+	if err != nil {
+		return nil, SendGmailDraftOutput{}, err
+	}
+
+	sent, err := srv.Users.Drafts.Send("me", &gmail.Draft{Id: input.DraftID}).Context(ctx).Do()
+	if err != nil {
+		return nil, SendGmailDraftOutput{}, fmt.Errorf("failed to send draft: %w", err)
+	}
+
+	resp := fmt.Sprintf("Draft sent successfully:\n  Message ID: %s\n  Thread ID: %s", sent.Id, sent.ThreadId)
+	return nil, SendGmailDraftOutput{Result: resp}, nil
+}
+
+// registerGmailDraftTools registers the draft tools. Sending a draft is only
+// offered when sending is enabled too, so agents that may only prepare mail
+// leave it for a human to send.
+func registerGmailDraftTools(server *mcp.Server) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "create_gmail_draft",
+		Description: "Create a Gmail draft for review; set replyToMessageId to draft a reply in the original thread",
+	}, CreateGmailDraft)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_gmail_drafts",
+		Description: "List Gmail drafts with recipients, subject and snippet",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, ListGmailDrafts)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_gmail_draft",
+		Description: "Read a Gmail draft: headers, body text and attachments",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, GetGmailDraft)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "update_gmail_draft",
+		Description: "Replace a Gmail draft's recipients, subject, body and attachments; reply drafts stay in their thread",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(true), IdempotentHint: true},
+	}, UpdateGmailDraft)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "delete_gmail_draft",
+		Description: "Permanently delete a Gmail draft (requires confirm)",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(true), IdempotentHint: true},
+	}, DeleteGmailDraft)
+
+	if gmailSendEnabled() {
+		mcp.AddTool(server, &mcp.Tool{
+			Name:        "send_gmail_draft",
+			Description: "Send an existing Gmail draft",
+			Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(false)},
+		}, SendGmailDraft)
+	}
+}
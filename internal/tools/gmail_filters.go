@@ -0,0 +1,298 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.orx.me/mcp/google-workspace/internal/utils"
+	"google.golang.org/api/gmail/v1"
+)
+
+// ListGmailFiltersInput defines input for list_gmail_filters tool
+type ListGmailFiltersInput struct {
+	Email string `json:"email" jsonschema:"Email address to access Gmail"`
+}
+
+// ListGmailFiltersOutput defines output for list_gmail_filters tool
+type ListGmailFiltersOutput struct {
+	Filters string `json:"filters" jsonschema:"Filters with their IDs, criteria and actions"`
+}
+
+// CreateGmailFilterInput defines input for create_gmail_filter tool
+type CreateGmailFilterInput struct {
+	Email          string   `json:"email" jsonschema:"Email address to access Gmail"`
+	From           string   `json:"from,omitempty" jsonschema:"Match messages from this sender"`
+	To             string   `json:"to,omitempty" jsonschema:"Match messages to this recipient, including Cc and Bcc"`
+	Subject        string   `json:"subject,omitempty" jsonschema:"Match messages whose subject contains this text"`
+	Query          string   `json:"query,omitempty" jsonschema:"Match messages that satisfy this Gmail search query"`
+	NegatedQuery   string   `json:"negatedQuery,omitempty" jsonschema:"Skip messages that satisfy this Gmail search query"`
+	HasAttachment  bool     `json:"hasAttachment,omitempty" jsonschema:"Only match messages with attachments"`
+	ExcludeChats   bool     `json:"excludeChats,omitempty" jsonschema:"Do not match chats"`
+	Size           int64    `json:"size,omitempty" jsonschema:"Match messages larger or smaller than this many bytes (see sizeComparison)"`
+	SizeComparison string   `json:"sizeComparison,omitempty" jsonschema:"How size is compared: larger or smaller (default larger)"`
+	Actions        []string `json:"actions,omitempty" jsonschema:"Shortcuts: archive, read, star, important, not_important, trash, never_spam"`
+	AddLabels      []string `json:"addLabels,omitempty" jsonschema:"Label names or IDs to apply to matching messages"`
+	RemoveLabels   []string `json:"removeLabels,omitempty" jsonschema:"Label names or IDs to remove from matching messages"`
+	Forward        string   `json:"forward,omitempty" jsonschema:"Verified forwarding address to forward matching messages to"`
+}
+
+// CreateGmailFilterOutput defines output for create_gmail_filter tool
+type CreateGmailFilterOutput struct {
+	Result string `json:"result" jsonschema:"Created filter ID, criteria and actions"`
+}
+
+// DeleteGmailFilterInput defines input for delete_gmail_filter tool
+type DeleteGmailFilterInput struct {
+	Email    string `json:"email" jsonschema:"Email address to access Gmail"`
+	FilterID string `json:"filterId" jsonschema:"Filter ID (from list_gmail_filters)"`
+	Confirm  bool   `json:"confirm" jsonschema:"Must be true; the filter cannot be recovered"`
+}
+
+// DeleteGmailFilterOutput defines output for delete_gmail_filter tool
+type DeleteGmailFilterOutput struct {
+	Result string `json:"result" jsonschema:"Deletion result"`
+}
+
+// gmailSettingsScopes lets filter, vacation and signature tools change
+// mailbox settings. gmailSharingScopes is needed for settings that send mail
+// elsewhere: forwarding and send-as aliases.
+var (
+	gmailSettingsScopes = []string{gmail.GmailSettingsBasicScope}
+	gmailSharingScopes  = []string{gmail.GmailSettingsSharingScope}
+)
+
+// gmailSettingsEnabled reports whether GMAIL_ENABLE_SETTINGS turns on the
+// tools that change mailbox settings.
+func gmailSettingsEnabled() bool {
+	return envFlag("GMAIL_ENABLE_SETTINGS")
+}
+
+// gmailFilterActions are shortcuts that only make sense in a filter, on top of
+// the triage shortcuts of gmailModifyActions.
+var gmailFilterActions = map[string]struct{ add, remove string }{
+	"trash":      {add: "TRASH"},
+	"never_spam": {remove: "SPAM"},
+}
+
+// newGmailFilterCriteria builds filter criteria from input, requiring at least
+// one condition.
+func newGmailFilterCriteria(input CreateGmailFilterInput) (*gmail.FilterCriteria, error) {
+	c := &gmail.FilterCriteria{
+		From:          input.From,
+		To:            input.To,
+		Subject:       input.Subject,
+		Query:         input.Query,
+		NegatedQuery:  input.NegatedQuery,
+		HasAttachment: input.HasAttachment,
+		ExcludeChats:  input.ExcludeChats,
+		Size:          input.Size,
+	}
+	if input.Size > 0 {
+		switch input.SizeComparison {
+		case "", "larger":
+			c.SizeComparison = "larger"
+		case "smaller":
+			c.SizeComparison = "smaller"
+		default:
+			return nil, fmt.Errorf("invalid sizeComparison %q: expected larger or smaller", input.SizeComparison)
+		}
+	}
+	if c.From == "" && c.To == "" && c.Subject == "" && c.Query == "" && c.NegatedQuery == "" && !c.HasAttachment && c.Size == 0 {
+		return nil, fmt.Errorf("set at least one criterion: from, to, subject, query, negatedQuery, hasAttachment or size")
+	}
+	return c, nil
+}
+
+// newGmailFilterAction resolves actions and label names to a filter action.
+func newGmailFilterAction(idx *gmailLabelIndex, input CreateGmailFilterInput) (*gmail.FilterAction, error) {
+	action := &gmail.FilterAction{Forward: input.Forward}
+
+	var actions []string
+	add, remove := append([]string(nil), input.AddLabels...), append([]string(nil), input.RemoveLabels...)
+	for _, a := range input.Actions {
+		if change, ok := gmailFilterActions[strings.ToLower(a)]; ok {
+			if change.add != "" {
+				add = append(add, change.add)
+			}
+			if change.remove != "" {
+				remove = append(remove, change.remove)
+			}
+			continue
+		}
+		actions = append(actions, a)
+	}
+
+	if len(actions) == 0 && len(add) == 0 && len(remove) == 0 {
+		if action.Forward == "" {
+			return nil, fmt.Errorf("set at least one action: actions, addLabels, removeLabels or forward")
+		}
+		return action, nil
+	}
+	var err error
+	if action.AddLabelIds, action.RemoveLabelIds, err = idx.labelChanges(actions, add, remove); err != nil {
+		return nil, err
+	}
+	return action, nil
+}
+
+// describeGmailFilterCriteria renders criteria as Gmail search operators.
+func describeGmailFilterCriteria(c *gmail.FilterCriteria) string {
+	if c == nil {
+		return "(none)"
+	}
+	var parts []string
+	add := func(op, v string) {
+		if v != "" {
+			parts = append(parts, fmt.Sprintf("%s:(%s)", op, v))
+		}
+	}
+	add("from", c.From)
+	add("to", c.To)
+	add("subject", c.Subject)
+	if c.Query != "" {
+		parts = append(parts, c.Query)
+	}
+	if c.NegatedQuery != "" {
+		parts = append(parts, fmt.Sprintf("-{%s}", c.NegatedQuery))
+	}
+	if c.HasAttachment {
+		parts = append(parts, "has:attachment")
+	}
+	if c.ExcludeChats {
+		parts = append(parts, "-in:chats")
+	}
+	if c.Size > 0 {
+		parts = append(parts, fmt.Sprintf("%s:%d", c.SizeComparison, c.Size))
+	}
+	if len(parts) == 0 {
+		return "(none)"
+	}
+	return strings.Join(parts, " ")
+}
+
+// describeGmailFilterAction renders an action with label names resolved.
+func describeGmailFilterAction(a *gmail.FilterAction, idx *gmailLabelIndex) string {
+	if a == nil {
+		return "(none)"
+	}
+	name := func(id string) string {
+		if l, ok := idx.byID[id]; ok {
+			return l.Name
+		}
+		return id
+	}
+	var parts []string
+	for _, id := range a.AddLabelIds {
+		parts = append(parts, "add "+name(id))
+	}
+	for _, id := range a.RemoveLabelIds {
+		parts = append(parts, "remove "+name(id))
+	}
+	if a.Forward != "" {
+		parts = append(parts, "forward to "+a.Forward)
+	}
+	if len(parts) == 0 {
+		return "(none)"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatGmailFilter renders one filter.
+func formatGmailFilter(f *gmail.Filter, idx *gmailLabelIndex) string {
+	return fmt.Sprintf("- ID: %s\n  Criteria: %s\n  Action: %s\n",
+		f.Id, describeGmailFilterCriteria(f.Criteria), describeGmailFilterAction(f.Action, idx))
+}
+
+// ListGmailFilters handles the list_gmail_filters tool call
+func ListGmailFilters(ctx context.Context, req *mcp.CallToolRequest, input ListGmailFiltersInput) (*mcp.CallToolResult, ListGmailFiltersOutput, error) {
+	srv, err := utils.NewGmailClient(input.Email)
+	if err != nil {
+		return nil, ListGmailFiltersOutput{}, err
+	}
+
+	list, err := srv.Users.Settings.Filters.List("me").Context(ctx).Do()
+	if err != nil {
+		return nil, ListGmailFiltersOutput{}, fmt.Errorf("failed to list filters: %w", err)
+	}
+	if len(list.Filter) == 0 {
+		return nil, ListGmailFiltersOutput{Filters: "No filters found"}, nil
+	}
+	idx, err := loadGmailLabelIndex(ctx, srv)
+	if err != nil {
+		return nil, ListGmailFiltersOutput{}, err
+	}
+
+	var resp strings.Builder
+	resp.WriteString(fmt.Sprintf("Filters (%d):\n", len(list.Filter)))
+	for _, f := range list.Filter {
+		resp.WriteString(formatGmailFilter(f, idx))
+	}
+	return nil, ListGmailFiltersOutput{Filters: resp.String()}, nil
+}
+
+// CreateGmailFilter handles the create_gmail_filter tool call
+func CreateGmailFilter(ctx context.Context, req *mcp.CallToolRequest, input CreateGmailFilterInput) (*mcp.CallToolResult, CreateGmailFilterOutput, error) {
+	criteria, err := newGmailFilterCriteria(input)
+	if err != nil {
+		return nil, CreateGmailFilterOutput{}, err
+	}
+
+	scopes := gmailSettingsScopes
+	if input.Forward != "" {
+		scopes = []string{gmail.GmailSettingsBasicScope, gmail.GmailSettingsSharingScope}
+	}
+	srv, err := utils.NewGmailClient(input.Email, scopes...)
+	if err != nil {
+		return nil, CreateGmailFilterOutput{}, err
+	}
+
+	idx, err := loadGmailLabelIndex(ctx, srv)
+	if err != nil {
+		return nil, CreateGmailFilterOutput{}, err
+	}
+	action, err := newGmailFilterAction(idx, input)
+	if err != nil {
+		return nil, CreateGmailFilterOutput{}, err
+	}
+
+	filter, err := srv.Users.Settings.Filters.Create("me", &gmail.Filter{Criteria: criteria, Action: action}).Context(ctx).Do()
+	if err != nil {
+		return nil, CreateGmailFilterOutput{}, fmt.Errorf("failed to create filter: %w", err)
+	}
+
+	return nil, CreateGmailFilterOutput{Result: "Filter created successfully:\n" + formatGmailFilter(filter, idx)}, nil
+}
+
+// DeleteGmailFilter handles the delete_gmail_filter tool call
+func DeleteGmailFilter(ctx context.Context, req *mcp.CallToolRequest, input DeleteGmailFilterInput) (*mcp.CallToolResult, DeleteGmailFilterOutput, error) {
+	if !input.Confirm {
+		return nil, DeleteGmailFilterOutput{}, fmt.Errorf("deleting a filter requires confirm to be true")
+	}
+
+	srv, err := utils.NewGmailClient(input.Email, gmailSettingsScopes...)
+	if err != nil {
+		return nil, DeleteGmailFilterOutput{}, err
+	}
+
+	if err := srv.Users.Settings.Filters.Delete("me", input.FilterID).Context(ctx).Do(); err != nil {
+		return nil, DeleteGmailFilterOutput{}, fmt.Errorf("failed to delete filter: %w", err)
+	}
+
+	return nil, DeleteGmailFilterOutput{Result: fmt.Sprintf("Filter %s deleted; messages it already changed are left as they are", input.FilterID)}, nil
+}
+
+// registerGmailFilterTools registers the tools that change filters.
+func registerGmailFilterTools(server *mcp.Server) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "create_gmail_filter",
+		Description: "Create a Gmail filter that labels, archives, stars, trashes or forwards incoming messages matching its criteria",
+	}, CreateGmailFilter)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "delete_gmail_filter",
+		Description: "Delete a Gmail filter (requires confirm)",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(true), IdempotentHint: true},
+	}, DeleteGmailFilter)
+}
@@ -0,0 +1,314 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.orx.me/mcp/google-workspace/internal/utils"
+	admin "google.golang.org/api/admin/directory/v1"
+	"google.golang.org/api/gmail/v1"
+)
+
+// GetGmailForwardingInput defines input for get_gmail_forwarding tool
+type GetGmailForwardingInput struct {
+	Email string `json:"email" jsonschema:"Email address to access Gmail"`
+}
+
+// GetGmailForwardingOutput defines output for get_gmail_forwarding tool
+type GetGmailForwardingOutput struct {
+	Forwarding string `json:"forwarding" jsonschema:"Auto-forwarding setting and forwarding addresses with their verification status"`
+}
+
+// AddGmailForwardingAddressInput defines input for add_gmail_forwarding_address tool
+type AddGmailForwardingAddressInput struct {
+	Email           string `json:"email" jsonschema:"Email address to access Gmail"`
+	ForwardingEmail string `json:"forwardingEmail" jsonschema:"Address to allow forwarding to; external addresses are sent a verification email"`
+}
+
+// AddGmailForwardingAddressOutput defines output for add_gmail_forwarding_address tool
+type AddGmailForwardingAddressOutput struct {
+	Result string `json:"result" jsonschema:"Forwarding address and its verification status"`
+}
+
+// DeleteGmailForwardingAddressInput defines input for delete_gmail_forwarding_address tool
+type DeleteGmailForwardingAddressInput struct {
+	Email           string `json:"email" jsonschema:"Email address to access Gmail"`
+	ForwardingEmail string `json:"forwardingEmail" jsonschema:"Forwarding address to remove"`
+	Confirm         bool   `json:"confirm" jsonschema:"Must be true; auto-forwarding and filters that use the address stop forwarding"`
+}
+
+// DeleteGmailForwardingAddressOutput defines output for delete_gmail_forwarding_address tool
+type DeleteGmailForwardingAddressOutput struct {
+	Result string `json:"result" jsonschema:"Deletion result"`
+}
+
+// SetGmailAutoForwardingInput defines input for set_gmail_auto_forwarding tool
+type SetGmailAutoForwardingInput struct {
+	Email           string `json:"email" jsonschema:"Email address to access Gmail"`
+	Enabled         bool   `json:"enabled" jsonschema:"Whether all incoming mail is forwarded"`
+	ForwardingEmail string `json:"forwardingEmail,omitempty" jsonschema:"Verified forwarding address to send mail to (required when enabled)"`
+	Disposition     string `json:"disposition,omitempty" jsonschema:"What happens to the original: leaveInInbox, archive, trash or markRead (default leaveInInbox)"`
+}
+
+// SetGmailAutoForwardingOutput defines output for set_gmail_auto_forwarding tool
+type SetGmailAutoForwardingOutput struct {
+	Result string `json:"result" jsonschema:"Updated auto-forwarding setting"`
+}
+
+// AuditGmailForwardingInput defines input for audit_gmail_forwarding tool
+type AuditGmailForwardingInput struct {
+	Email           string   `json:"email,omitempty" jsonschema:"User whose mailbox to audit (required unless allUsers is set)"`
+	AllUsers        bool     `json:"allUsers,omitempty" jsonschema:"Audit every user in the directory domain instead of a single user"`
+	Domain          string   `json:"domain,omitempty" jsonschema:"Directory domain whose users to audit when allUsers is set"`
+	InternalDomains []string `json:"internalDomains,omitempty" jsonschema:"Domains considered internal (default: the domain of email, or domain when allUsers is set)"`
+}
+
+// AuditGmailForwardingOutput defines output for audit_gmail_forwarding tool
+type AuditGmailForwardingOutput struct {
+	Report string `json:"report" jsonschema:"Mailboxes that auto-forward or filter-forward mail outside the internal domains"`
+}
+
+// gmailForwardingDispositions are the values Gmail accepts for what happens to
+// a message after it is auto-forwarded.
+var gmailForwardingDispositions = map[string]bool{
+	"leaveInInbox": true,
+	"archive":      true,
+	"trash":        true,
+	"markRead":     true,
+}
+
+// describeGmailAutoForwarding renders the auto-forwarding setting on one line.
+func describeGmailAutoForwarding(a *gmail.AutoForwarding) string {
+	if a == nil || !a.Enabled {
+		return "disabled"
+	}
+	return fmt.Sprintf("enabled, to %s (original: %s)", a.EmailAddress, a.Disposition)
+}
+
+// gmailForwardingFindings returns the ways a mailbox sends mail outside the
+// internal domains: auto-forwarding and filters with a forward action.
+func gmailForwardingFindings(auto *gmail.AutoForwarding, filters []*gmail.Filter, internal map[string]bool) []string {
+	var findings []string
+	if auto != nil && auto.Enabled && !internal[emailDomain(auto.EmailAddress)] {
+		findings = append(findings, "auto-forwarding "+describeGmailAutoForwarding(auto))
+	}
+	for _, f := range filters {
+		if f.Action == nil || f.Action.Forward == "" || internal[emailDomain(f.Action.Forward)] {
+			continue
+		}
+		findings = append(findings, fmt.Sprintf("filter %s forwards %s to %s", f.Id, describeGmailFilterCriteria(f.Criteria), f.Action.Forward))
+	}
+	return findings
+}
+
+// auditGmailForwarding reads one mailbox's auto-forwarding setting and filters.
+func auditGmailForwarding(ctx context.Context, email string, internal map[string]bool) ([]string, error) {
+	srv, err := utils.NewGmailClient(email)
+	if err != nil {
+		return nil, err
+	}
+	auto, err := srv.Users.Settings.GetAutoForwarding("me").Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get auto-forwarding: %w", err)
+	}
+	filters, err := srv.Users.Settings.Filters.List("me").Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list filters: %w", err)
+	}
+	return gmailForwardingFindings(auto, filters.Filter, internal), nil
+}
+
+// GetGmailForwarding handles the get_gmail_forwarding tool call
+func GetGmailForwarding(ctx context.Context, req *mcp.CallToolRequest, input GetGmailForwardingInput) (*mcp.CallToolResult, GetGmailForwardingOutput, error) {
+	srv, err := utils.NewGmailClient(input.Email)
+	if err != nil {
+		return nil, GetGmailForwardingOutput{}, err
+	}
+
+	auto, err := srv.Users.Settings.GetAutoForwarding("me").Context(ctx).Do()
+	if err != nil {
+		return nil, GetGmailForwardingOutput{}, fmt.Errorf("failed to get auto-forwarding: %w", err)
+	}
+	addresses, err := srv.Users.Settings.ForwardingAddresses.List("me").Context(ctx).Do()
+	if err != nil {
+		return nil, GetGmailForwardingOutput{}, fmt.Errorf("failed to list forwarding addresses: %w", err)
+	}
+
+	var resp strings.Builder
+	resp.WriteString(fmt.Sprintf("Auto-forwarding: %s\n", describeGmailAutoForwarding(auto)))
+	resp.WriteString(fmt.Sprintf("\nForwarding addresses (%d):\n", len(addresses.ForwardingAddresses)))
+	for _, a := range addresses.ForwardingAddresses {
+		resp.WriteString(fmt.Sprintf("- %s (%s)\n", a.ForwardingEmail, a.VerificationStatus))
+	}
+	return nil, GetGmailForwardingOutput{Forwarding: resp.String()}, nil
+}
+
+// AddGmailForwardingAddress handles the add_gmail_forwarding_address tool call
+func AddGmailForwardingAddress(ctx context.Context, req *mcp.CallToolRequest, input AddGmailForwardingAddressInput) (*mcp.CallToolResult, AddGmailForwardingAddressOutput, error) {
+	srv, err := utils.NewGmailClient(input.Email, gmailSharingScopes...)
+	if err != nil {
+		return nil, AddGmailForwardingAddressOutput{}, err
+	}
+
+	created, err := srv.Users.Settings.ForwardingAddresses.Create("me", &gmail.ForwardingAddress{
+		ForwardingEmail: input.ForwardingEmail,
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, AddGmailForwardingAddressOutput{}, fmt.Errorf("failed to add forwarding address: %w", err)
+	}
+
+	resp := fmt.Sprintf("Forwarding address added:\n  Address: %s\n  Verification: %s", created.ForwardingEmail, created.VerificationStatus)
+	if created.VerificationStatus == "pending" {
+		resp += "\nA verification email was sent; the address can be used once its owner confirms it."
+	}
+	return nil, AddGmailForwardingAddressOutput{Result: resp}, nil
+}
+
+// DeleteGmailForwardingAddress handles the delete_gmail_forwarding_address tool call
+func DeleteGmailForwardingAddress(ctx context.Context, req *mcp.CallToolRequest, input DeleteGmailForwardingAddressInput) (*mcp.CallToolResult, DeleteGmailForwardingAddressOutput, error) {
+	if !input.Confirm {
+		return nil, DeleteGmailForwardingAddressOutput{}, fmt.Errorf("deleting a forwarding address requires confirm to be true")
+	}
+
+	srv, err := utils.NewGmailClient(input.Email, gmailSharingScopes...)
+	if err != nil {
+		return nil, DeleteGmailForwardingAddressOutput{}, err
+	}
+
+	if err := srv.Users.Settings.ForwardingAddresses.Delete("me", input.ForwardingEmail).Context(ctx).Do(); err != nil {
+		return nil, DeleteGmailForwardingAddressOutput{}, fmt.Errorf("failed to delete forwarding address: %w", err)
+	}
+
+	return nil, DeleteGmailForwardingAddressOutput{Result: fmt.Sprintf("Forwarding address %s deleted", input.ForwardingEmail)}, nil
+}
+
+// SetGmailAutoForwarding handles the set_gmail_auto_forwarding tool call
+func SetGmailAutoForwarding(ctx context.Context, req *mcp.CallToolRequest, input SetGmailAutoForwardingInput) (*mcp.CallToolResult, SetGmailAutoForwardingOutput, error) {
+	settings := &gmail.AutoForwarding{Enabled: input.Enabled, ForceSendFields: []string{"Enabled"}}
+	if input.Enabled {
+		if input.ForwardingEmail == "" {
+			return nil, SetGmailAutoForwardingOutput{}, fmt.Errorf("forwardingEmail is required when enabled is true")
+		}
+		disposition := input.Disposition
+		if disposition == "" {
+			disposition = "leaveInInbox"
+		}
+		if !gmailForwardingDispositions[disposition] {
+			return nil, SetGmailAutoForwardingOutput{}, fmt.Errorf("invalid disposition %q: expected leaveInInbox, archive, trash or markRead", disposition)
+		}
+		settings.EmailAddress = input.ForwardingEmail
+		settings.Disposition = disposition
+	}
+
+	srv, err := utils.NewGmailClient(input.Email, gmailSharingScopes...)
+	if err != nil {
+		return nil, SetGmailAutoForwardingOutput{}, err
+	}
+
+	updated, err := srv.Users.Settings.UpdateAutoForwarding("me", settings).Context(ctx).Do()
+	if err != nil {
+		return nil, SetGmailAutoForwardingOutput{}, fmt.Errorf("failed to update auto-forwarding: %w", err)
+	}
+
+	return nil, SetGmailAutoForwardingOutput{Result: "Auto-forwarding: " + describeGmailAutoForwarding(updated)}, nil
+}
+
+// AuditGmailForwarding handles the audit_gmail_forwarding tool call
+func AuditGmailForwarding(ctx context.Context, req *mcp.CallToolRequest, input AuditGmailForwardingInput) (*mcp.CallToolResult, AuditGmailForwardingOutput, error) {
+	if input.AllUsers && input.Domain == "" {
+		return nil, AuditGmailForwardingOutput{}, fmt.Errorf("domain is required when allUsers is set")
+	}
+	if !input.AllUsers && input.Email == "" {
+		return nil, AuditGmailForwardingOutput{}, fmt.Errorf("email is required unless allUsers is set")
+	}
+
+	internal := map[string]bool{}
+	for _, d := range input.InternalDomains {
+		internal[strings.ToLower(d)] = true
+	}
+	if len(internal) == 0 {
+		if input.AllUsers {
+			internal[strings.ToLower(input.Domain)] = true
+		} else {
+			internal[emailDomain(input.Email)] = true
+		}
+	}
+
+	users := []string{input.Email}
+	if input.AllUsers {
+		client, err := utils.DefaultClient()
+		if err != nil {
+			return nil, AuditGmailForwardingOutput{}, err
+		}
+		users = nil
+		err = client.Users.List().Domain(input.Domain).Pages(ctx, func(page *admin.Users) error {
+			for _, user := range page.Users {
+				if !user.Suspended {
+					users = append(users, user.PrimaryEmail)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, AuditGmailForwardingOutput{}, fmt.Errorf("failed to list users: %w", err)
+		}
+	}
+
+	var report, userErrors strings.Builder
+	flagged := 0
+	for i, email := range users {
+		notifyProgress(ctx, req, float64(i), float64(len(users)), "Auditing "+email)
+		findings, err := auditGmailForwarding(ctx, email, internal)
+		if err != nil {
+			if !input.AllUsers {
+				return nil, AuditGmailForwardingOutput{}, fmt.Errorf("failed to audit %s: %w", email, err)
+			}
+			userErrors.WriteString(fmt.Sprintf("- %s: %v\n", email, err))
+			continue
+		}
+		if len(findings) == 0 {
+			continue
+		}
+		flagged++
+		report.WriteString(fmt.Sprintf("- %s:\n", email))
+		for _, f := range findings {
+			report.WriteString(fmt.Sprintf("    %s\n", f))
+		}
+	}
+
+	var resp strings.Builder
+	resp.WriteString(fmt.Sprintf("Forwarding audit: %d user(s) inspected, %d forwarding outside the internal domains\n\n", len(users), flagged))
+	if flagged == 0 {
+		resp.WriteString("No mailboxes forward mail externally.\n")
+	} else {
+		resp.WriteString(report.String())
+	}
+	if userErrors.Len() > 0 {
+		resp.WriteString("\nUsers that could not be audited:\n")
+		resp.WriteString(userErrors.String())
+	}
+
+	return nil, AuditGmailForwardingOutput{Report: resp.String()}, nil
+}
+
+// registerGmailForwardingTools registers the tools that change forwarding.
+func registerGmailForwardingTools(server *mcp.Server) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "add_gmail_forwarding_address",
+		Description: "Add a Gmail forwarding address; external addresses must confirm a verification email before use",
+	}, AddGmailForwardingAddress)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "delete_gmail_forwarding_address",
+		Description: "Delete a Gmail forwarding address (requires confirm)",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(true), IdempotentHint: true},
+	}, DeleteGmailForwardingAddress)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "set_gmail_auto_forwarding",
+		Description: "Turn Gmail auto-forwarding of all incoming mail on or off and choose what happens to the original",
+		Annotations: &mcp.ToolAnnotations{IdempotentHint: true},
+	}, SetGmailAutoForwarding)
+}
@@ -0,0 +1,561 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.orx.me/mcp/google-workspace/internal/utils"
+	"google.golang.org/api/gmail/v1"
+)
+
+// ListGmailLabelsInput defines input for list_gmail_labels tool
+type ListGmailLabelsInput struct {
+	Email         string `json:"email" jsonschema:"Email address to access Gmail"`
+	IncludeCounts bool   `json:"includeCounts,omitempty" jsonschema:"Include total and unread message counts (one extra request per label)"`
+}
+
+// ListGmailLabelsOutput defines output for list_gmail_labels tool
+type ListGmailLabelsOutput struct {
+	Labels string `json:"labels" jsonschema:"System labels and the nested tree of user labels"`
+}
+
+// CreateGmailLabelInput defines input for create_gmail_label tool
+type CreateGmailLabelInput struct {
+	Email                 string `json:"email" jsonschema:"Email address to access Gmail"`
+	Name                  string `json:"name" jsonschema:"Label name; use Parent/Child to nest it or set parent"`
+	Parent                string `json:"parent,omitempty" jsonschema:"Existing label name or ID to nest the new label under"`
+	BackgroundColor       string `json:"backgroundColor,omitempty" jsonschema:"Background color as #rrggbb from Gmail's label palette (requires textColor)"`
+	TextColor             string `json:"textColor,omitempty" jsonschema:"Text color as #rrggbb from Gmail's label palette (requires backgroundColor)"`
+	LabelListVisibility   string `json:"labelListVisibility,omitempty" jsonschema:"Visibility in the label list: labelShow, labelShowIfUnread or labelHide"`
+	MessageListVisibility string `json:"messageListVisibility,omitempty" jsonschema:"Visibility on messages in the message list: show or hide"`
+}
+
+// CreateGmailLabelOutput defines output for create_gmail_label tool
+type CreateGmailLabelOutput struct {
+	Result string `json:"result" jsonschema:"Created label information"`
+}
+
+// UpdateGmailLabelInput defines input for update_gmail_label tool
+type UpdateGmailLabelInput struct {
+	Email                 string `json:"email" jsonschema:"Email address to access Gmail"`
+	Label                 string `json:"label" jsonschema:"Label name or ID to update"`
+	Name                  string `json:"name,omitempty" jsonschema:"New full label name, e.g. Clients/Acme; nested labels are moved along"`
+	BackgroundColor       string `json:"backgroundColor,omitempty" jsonschema:"Background color as #rrggbb from Gmail's label palette (requires textColor)"`
+	TextColor             string `json:"textColor,omitempty" jsonschema:"Text color as #rrggbb from Gmail's label palette (requires backgroundColor)"`
+	LabelListVisibility   string `json:"labelListVisibility,omitempty" jsonschema:"Visibility in the label list: labelShow, labelShowIfUnread or labelHide"`
+	MessageListVisibility string `json:"messageListVisibility,omitempty" jsonschema:"Visibility on messages in the message list: show or hide"`
+}
+
+// UpdateGmailLabelOutput defines output for update_gmail_label tool
+type UpdateGmailLabelOutput struct {
+	Result string `json:"result" jsonschema:"Updated label information"`
+}
+
+// DeleteGmailLabelInput defines input for delete_gmail_label tool
+type DeleteGmailLabelInput struct {
+	Email   string `json:"email" jsonschema:"Email address to access Gmail"`
+	Label   string `json:"label" jsonschema:"Label name or ID to delete"`
+	Confirm bool   `json:"confirm" jsonschema:"Must be true; the label is removed from every message and cannot be recovered"`
+}
+
+// DeleteGmailLabelOutput defines output for delete_gmail_label tool
+type DeleteGmailLabelOutput struct {
+	Result string `json:"result" jsonschema:"Deletion result"`
+}
+
+// ModifyGmailMessagesInput defines input for modify_gmail_messages tool
+type ModifyGmailMessagesInput struct {
+	Email        string   `json:"email" jsonschema:"Email address to access Gmail"`
+	MessageIDs   []string `json:"messageIds,omitempty" jsonschema:"Message IDs to modify"`
+	ThreadIDs    []string `json:"threadIds,omitempty" jsonschema:"Thread IDs to modify; every message in each thread is changed"`
+	Actions      []string `json:"actions,omitempty" jsonschema:"Shortcuts: archive, inbox, read, unread, star, unstar, important, not_important"`
+	AddLabels    []string `json:"addLabels,omitempty" jsonschema:"Label names or IDs to add"`
+	RemoveLabels []string `json:"removeLabels,omitempty" jsonschema:"Label names or IDs to remove"`
+}
+
+// ModifyGmailMessagesOutput defines output for modify_gmail_messages tool
+type ModifyGmailMessagesOutput struct {
+	Result string `json:"result" jsonschema:"Number of messages and threads changed and any failures"`
+}
+
+// gmailModifyScopes lets label and triage tools change labels on messages.
+var gmailModifyScopes = []string{gmail.GmailModifyScope}
+
+// gmailModifyEnabled reports whether GMAIL_ENABLE_MODIFY turns on the tools
+// that change labels, which need the gmail.modify scope.
+func gmailModifyEnabled() bool {
+	return envFlag("GMAIL_ENABLE_MODIFY")
+}
+
+// gmailBatchModifyLimit is the most message IDs one batchModify call accepts.
+const gmailBatchModifyLimit = 1000
+
+// gmailLabelColorPattern matches the lowercase #rrggbb colors Gmail accepts.
+var gmailLabelColorPattern = regexp.MustCompile(`^#[0-9a-f]{6}$`)
+
+// gmailModifyActions maps triage shortcuts to the system labels they change.
+var gmailModifyActions = map[string]struct{ add, remove string }{
+	"archive":       {remove: "INBOX"},
+	"inbox":         {add: "INBOX"},
+	"read":          {remove: "UNREAD"},
+	"unread":        {add: "UNREAD"},
+	"star":          {add: "STARRED"},
+	"unstar":        {remove: "STARRED"},
+	"important":     {add: "IMPORTANT"},
+	"not_important": {remove: "IMPORTANT"},
+}
+
+// gmailLabelIndex looks labels up by ID or by case-insensitive name.
+type gmailLabelIndex struct {
+	labels []*gmail.Label
+	byID   map[string]*gmail.Label
+	byName map[string]*gmail.Label
+}
+
+// newGmailLabelIndex indexes labels.
+func newGmailLabelIndex(labels []*gmail.Label) *gmailLabelIndex {
+	idx := &gmailLabelIndex{
+		labels: labels,
+		byID:   make(map[string]*gmail.Label, len(labels)),
+		byName: make(map[string]*gmail.Label, len(labels)),
+	}
+	for _, l := range labels {
+		idx.byID[l.Id] = l
+		idx.byName[strings.ToLower(l.Name)] = l
+	}
+	return idx
+}
+
+// loadGmailLabelIndex lists the mailbox's labels.
+func loadGmailLabelIndex(ctx context.Context, srv *gmail.Service) (*gmailLabelIndex, error) {
+	list, err := srv.Users.Labels.List("me").Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list labels: %w", err)
+	}
+	return newGmailLabelIndex(list.Labels), nil
+}
+
+// find returns the label with the given ID or name.
+func (idx *gmailLabelIndex) find(nameOrID string) (*gmail.Label, error) {
+	if l, ok := idx.byID[nameOrID]; ok {
+		return l, nil
+	}
+	if l, ok := idx.byName[strings.ToLower(nameOrID)]; ok {
+		return l, nil
+	}
+	return nil, fmt.Errorf("label %q not found", nameOrID)
+}
+
+// children returns the labels nested anywhere below name.
+func (idx *gmailLabelIndex) children(name string) []*gmail.Label {
+	prefix := strings.ToLower(name) + "/"
+	var children []*gmail.Label
+	for _, l := range idx.labels {
+		if strings.HasPrefix(strings.ToLower(l.Name), prefix) {
+			children = append(children, l)
+		}
+	}
+	return children
+}
+
+// labelChanges resolves actions and label names to the label IDs to add and
+// remove. A label may not be both added and removed.
+func (idx *gmailLabelIndex) labelChanges(actions, add, remove []string) ([]string, []string, error) {
+	var addIDs, removeIDs []string
+	adding, removing := map[string]bool{}, map[string]bool{}
+	include := func(ids *[]string, set map[string]bool, id string) {
+		if !set[id] {
+			set[id] = true
+			*ids = append(*ids, id)
+		}
+	}
+
+	for _, a := range actions {
+		change, ok := gmailModifyActions[strings.ToLower(a)]
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown action %q: expected archive, inbox, read, unread, star, unstar, important or not_important", a)
+		}
+		if change.add != "" {
+			include(&addIDs, adding, change.add)
+		}
+		if change.remove != "" {
+			include(&removeIDs, removing, change.remove)
+		}
+	}
+	for _, names := range []struct {
+		list []string
+		ids  *[]string
+		set  map[string]bool
+	}{{add, &addIDs, adding}, {remove, &removeIDs, removing}} {
+		for _, name := range names.list {
+			l, err := idx.find(name)
+			if err != nil {
+				return nil, nil, err
+			}
+			include(names.ids, names.set, l.Id)
+		}
+	}
+
+	for id := range adding {
+		if removing[id] {
+			return nil, nil, fmt.Errorf("label %s cannot be both added and removed", id)
+		}
+	}
+	if len(addIDs) == 0 && len(removeIDs) == 0 {
+		return nil, nil, fmt.Errorf("nothing to change: set actions, addLabels or removeLabels")
+	}
+	return addIDs, removeIDs, nil
+}
+
+// newGmailLabelColor validates a background and text color pair.
+func newGmailLabelColor(background, text string) (*gmail.LabelColor, error) {
+	if background == "" && text == "" {
+		return nil, nil
+	}
+	if background == "" || text == "" {
+		return nil, fmt.Errorf("backgroundColor and textColor must be set together")
+	}
+	for _, c := range []string{background, text} {
+		if !gmailLabelColorPattern.MatchString(strings.ToLower(c)) {
+			return nil, fmt.Errorf("invalid color %q: expected #rrggbb", c)
+		}
+	}
+	return &gmail.LabelColor{BackgroundColor: strings.ToLower(background), TextColor: strings.ToLower(text)}, nil
+}
+
+// checkGmailLabelVisibility validates the visibility settings of a label.
+func checkGmailLabelVisibility(labelList, messageList string) error {
+	switch labelList {
+	case "", "labelShow", "labelShowIfUnread", "labelHide":
+	default:
+		return fmt.Errorf("invalid labelListVisibility %q: expected labelShow, labelShowIfUnread or labelHide", labelList)
+	}
+	switch messageList {
+	case "", "show", "hide":
+	default:
+		return fmt.Errorf("invalid messageListVisibility %q: expected show or hide", messageList)
+	}
+	return nil
+}
+
+// describeGmailLabel renders one label's details on a single line.
+func describeGmailLabel(l *gmail.Label) string {
+	details := []string{"ID: " + l.Id}
+	if l.Color != nil && l.Color.BackgroundColor != "" {
+		details = append(details, fmt.Sprintf("color: %s on %s", l.Color.TextColor, l.Color.BackgroundColor))
+	}
+	if l.MessagesTotal > 0 || l.MessagesUnread > 0 {
+		details = append(details, fmt.Sprintf("%d messages, %d unread", l.MessagesTotal, l.MessagesUnread))
+	}
+	if l.LabelListVisibility == "labelHide" {
+		details = append(details, "hidden")
+	}
+	return strings.Join(details, ", ")
+}
+
+// formatGmailLabels lists system labels, then user labels as a tree built
+// from their Parent/Child names.
+func formatGmailLabels(labels []*gmail.Label) string {
+	var system, user []*gmail.Label
+	for _, l := range labels {
+		if l.Type == "system" {
+			system = append(system, l)
+		} else {
+			user = append(user, l)
+		}
+	}
+	byName := func(ls []*gmail.Label) {
+		sort.Slice(ls, func(i, j int) bool { return strings.ToLower(ls[i].Name) < strings.ToLower(ls[j].Name) })
+	}
+	byName(system)
+	byName(user)
+
+	names := make(map[string]bool, len(user))
+	for _, l := range user {
+		names[strings.ToLower(l.Name)] = true
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("System labels (%d):\n", len(system)))
+	for _, l := range system {
+		b.WriteString(fmt.Sprintf("- %s (%s)\n", l.Name, describeGmailLabel(l)))
+	}
+	b.WriteString(fmt.Sprintf("\nUser labels (%d):\n", len(user)))
+	for _, l := range user {
+		// Indent under the nearest existing ancestor; a label whose parents
+		// do not exist is shown with its full name at the top level.
+		display, depth := l.Name, 0
+		parts := strings.Split(l.Name, "/")
+		for i := len(parts) - 1; i > 0; i-- {
+			if parent := strings.Join(parts[:i], "/"); names[strings.ToLower(parent)] {
+				display = strings.Join(parts[i:], "/")
+				depth = strings.Count(parent, "/") + 1
+				break
+			}
+		}
+		b.WriteString(fmt.Sprintf("%s- %s (%s)\n", strings.Repeat("  ", depth), display, describeGmailLabel(l)))
+	}
+	return b.String()
+}
+
+// ListGmailLabels handles the list_gmail_labels tool call
+func ListGmailLabels(ctx context.Context, req *mcp.CallToolRequest, input ListGmailLabelsInput) (*mcp.CallToolResult, ListGmailLabelsOutput, error) {
+	srv, err := utils.NewGmailClient(input.Email)
+	if err != nil {
+		return nil, ListGmailLabelsOutput{}, err
+	}
+
+	list, err := srv.Users.Labels.List("me").Context(ctx).Do()
+	if err != nil {
+		return nil, ListGmailLabelsOutput{}, fmt.Errorf("failed to list labels: %w", err)
+	}
+
+	labels := list.Labels
+	if input.IncludeCounts {
+		for i, l := range labels {
+			full, err := srv.Users.Labels.Get("me", l.Id).Context(ctx).Do()
+			if err != nil {
+				return nil, ListGmailLabelsOutput{}, fmt.Errorf("failed to get label %s: %w", l.Name, err)
+			}
+			labels[i] = full
+		}
+	}
+
+	return nil, ListGmailLabelsOutput{Labels: formatGmailLabels(labels)}, nil
+}
+
+// CreateGmailLabel handles the create_gmail_label tool call
+func CreateGmailLabel(ctx context.Context, req *mcp.CallToolRequest, input CreateGmailLabelInput) (*mcp.CallToolResult, CreateGmailLabelOutput, error) {
+	color, err := newGmailLabelColor(input.BackgroundColor, input.TextColor)
+	if err != nil {
+		return nil, CreateGmailLabelOutput{}, err
+	}
+	if err := checkGmailLabelVisibility(input.LabelListVisibility, input.MessageListVisibility); err != nil {
+		return nil, CreateGmailLabelOutput{}, err
+	}
+
+	srv, err := utils.NewGmailClient(input.Email, gmailModifyScopes...)
+	if err != nil {
+		return nil, CreateGmailLabelOutput{}, err
+	}
+
+	name := strings.Trim(input.Name, "/")
+	if input.Parent != "" {
+		idx, err := loadGmailLabelIndex(ctx, srv)
+		if err != nil {
+			return nil, CreateGmailLabelOutput{}, err
+		}
+		parent, err := idx.find(input.Parent)
+		if err != nil {
+			return nil, CreateGmailLabelOutput{}, err
+		}
+		name = parent.Name + "/" + name
+	}
+
+	label, err := srv.Users.Labels.Create("me", &gmail.Label{
+		Name:                  name,
+		Color:                 color,
+		LabelListVisibility:   input.LabelListVisibility,
+		MessageListVisibility: input.MessageListVisibility,
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, CreateGmailLabelOutput{}, fmt.Errorf("failed to create label: %w", err)
+	}
+
+	resp := fmt.Sprintf("Label created successfully:\n  Name: %s\n  %s", label.Name, describeGmailLabel(label))
+	return nil, CreateGmailLabelOutput{Result: resp}, nil
+}
+
+// UpdateGmailLabel handles the update_gmail_label tool call. Gmail nests
+// labels by name only, so renaming a label also renames every label below it.
+func UpdateGmailLabel(ctx context.Context, req *mcp.CallToolRequest, input UpdateGmailLabelInput) (*mcp.CallToolResult, UpdateGmailLabelOutput, error) {
+	color, err := newGmailLabelColor(input.BackgroundColor, input.TextColor)
+	if err != nil {
+		return nil, UpdateGmailLabelOutput{}, err
+	}
+	if err := checkGmailLabelVisibility(input.LabelListVisibility, input.MessageListVisibility); err != nil {
+		return nil, UpdateGmailLabelOutput{}, err
+	}
+	newName := strings.Trim(input.Name, "/")
+	if newName == "" && color == nil && input.LabelListVisibility == "" && input.MessageListVisibility == "" {
+		return nil, UpdateGmailLabelOutput{}, fmt.Errorf("nothing to update: set name, colors or visibility")
+	}
+
+	srv, err := utils.NewGmailClient(input.Email, gmailModifyScopes...)
+	if err != nil {
+		return nil, UpdateGmailLabelOutput{}, err
+	}
+
+	idx, err := loadGmailLabelIndex(ctx, srv)
+	if err != nil {
+		return nil, UpdateGmailLabelOutput{}, err
+	}
+	label, err := idx.find(input.Label)
+	if err != nil {
+		return nil, UpdateGmailLabelOutput{}, err
+	}
+	if label.Type == "system" {
+		return nil, UpdateGmailLabelOutput{}, fmt.Errorf("system label %s cannot be changed", label.Name)
+	}
+
+	oldName := label.Name
+	updated, err := srv.Users.Labels.Patch("me", label.Id, &gmail.Label{
+		Name:                  newName,
+		Color:                 color,
+		LabelListVisibility:   input.LabelListVisibility,
+		MessageListVisibility: input.MessageListVisibility,
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, UpdateGmailLabelOutput{}, fmt.Errorf("failed to update label: %w", err)
+	}
+
+	var resp strings.Builder
+	resp.WriteString(fmt.Sprintf("Label updated successfully:\n  Name: %s\n  %s\n", updated.Name, describeGmailLabel(updated)))
+	if newName != "" && updated.Name != oldName {
+		for _, child := range idx.children(oldName) {
+			childName := updated.Name + child.Name[len(oldName):]
+			if _, err := srv.Users.Labels.Patch("me", child.Id, &gmail.Label{Name: childName}).Context(ctx).Do(); err != nil {
+				return nil, UpdateGmailLabelOutput{}, fmt.Errorf("renamed %s but failed to move nested label %s: %w", oldName, child.Name, err)
+			}
+			resp.WriteString(fmt.Sprintf("  Moved nested label: %s -> %s\n", child.Name, childName))
+		}
+	}
+
+	return nil, UpdateGmailLabelOutput{Result: resp.String()}, nil
+}
+
+// DeleteGmailLabel handles the delete_gmail_label tool call
+func DeleteGmailLabel(ctx context.Context, req *mcp.CallToolRequest, input DeleteGmailLabelInput) (*mcp.CallToolResult, DeleteGmailLabelOutput, error) {
+	if !input.Confirm {
+		return nil, DeleteGmailLabelOutput{}, fmt.Errorf("deleting a label requires confirm to be true")
+	}
+
+	srv, err := utils.NewGmailClient(input.Email, gmailModifyScopes...)
+	if err != nil {
+		return nil, DeleteGmailLabelOutput{}, err
+	}
+
+	idx, err := loadGmailLabelIndex(ctx, srv)
+	if err != nil {
+		return nil, DeleteGmailLabelOutput{}, err
+	}
+	label, err := idx.find(input.Label)
+	if err != nil {
+		return nil, DeleteGmailLabelOutput{}, err
+	}
+	if label.Type == "system" {
+		return nil, DeleteGmailLabelOutput{}, fmt.Errorf("system label %s cannot be deleted", label.Name)
+	}
+
+	if err := srv.Users.Labels.Delete("me", label.Id).Context(ctx).Do(); err != nil {
+		return nil, DeleteGmailLabelOutput{}, fmt.Errorf("failed to delete label: %w", err)
+	}
+
+	resp := fmt.Sprintf("Label %s (%s) deleted; messages keep their other labels", label.Name, label.Id)
+	if children := idx.children(label.Name); len(children) > 0 {
+		resp += fmt.Sprintf("\nNested labels were kept (%d), e.g. %s", len(children), children[0].Name)
+	}
+	return nil, DeleteGmailLabelOutput{Result: resp}, nil
+}
+
+// ModifyGmailMessages handles the modify_gmail_messages tool call. Messages
+// are changed with batchModify, up to gmailBatchModifyLimit IDs per request;
+// threads have no batch endpoint and are changed one at a time. A failed
+// request is reported and the rest still run, so the result always says what
+// changed.
+func ModifyGmailMessages(ctx context.Context, req *mcp.CallToolRequest, input ModifyGmailMessagesInput) (*mcp.CallToolResult, ModifyGmailMessagesOutput, error) {
+	if len(input.MessageIDs) == 0 && len(input.ThreadIDs) == 0 {
+		return nil, ModifyGmailMessagesOutput{}, fmt.Errorf("set messageIds or threadIds")
+	}
+
+	srv, err := utils.NewGmailClient(input.Email, gmailModifyScopes...)
+	if err != nil {
+		return nil, ModifyGmailMessagesOutput{}, err
+	}
+
+	idx, err := loadGmailLabelIndex(ctx, srv)
+	if err != nil {
+		return nil, ModifyGmailMessagesOutput{}, err
+	}
+	add, remove, err := idx.labelChanges(input.Actions, input.AddLabels, input.RemoveLabels)
+	if err != nil {
+		return nil, ModifyGmailMessagesOutput{}, err
+	}
+
+	total := float64(len(input.MessageIDs) + len(input.ThreadIDs))
+	modified, threads := 0, 0
+	var failures []string
+	for start := 0; start < len(input.MessageIDs); start += gmailBatchModifyLimit {
+		end := min(start+gmailBatchModifyLimit, len(input.MessageIDs))
+		err := srv.Users.Messages.BatchModify("me", &gmail.BatchModifyMessagesRequest{
+			Ids:            input.MessageIDs[start:end],
+			AddLabelIds:    add,
+			RemoveLabelIds: remove,
+		}).Context(ctx).Do()
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("- messages %d-%d: %v", start+1, end, err))
+		} else {
+			modified += end - start
+		}
+		notifyProgress(ctx, req, float64(end), total, fmt.Sprintf("Processed %d messages", end))
+	}
+
+	for i, id := range input.ThreadIDs {
+		_, err := srv.Users.Threads.Modify("me", id, &gmail.ModifyThreadRequest{
+			AddLabelIds:    add,
+			RemoveLabelIds: remove,
+		}).Context(ctx).Do()
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("- thread %s: %v", id, err))
+		} else {
+			threads++
+		}
+		notifyProgress(ctx, req, float64(len(input.MessageIDs)+i+1), total, "Processed thread "+id)
+	}
+
+	var resp strings.Builder
+	resp.WriteString(fmt.Sprintf("Modified %d messages and %d threads\n", modified, threads))
+	if len(add) > 0 {
+		resp.WriteString(fmt.Sprintf("  Added labels: %s\n", strings.Join(add, ", ")))
+	}
+	if len(remove) > 0 {
+		resp.WriteString(fmt.Sprintf("  Removed labels: %s\n", strings.Join(remove, ", ")))
+	}
+	if len(failures) > 0 {
+		resp.WriteString(fmt.Sprintf("\nFailures (%d):\n", len(failures)))
+		resp.WriteString(strings.Join(failures, "\n"))
+		resp.WriteString("\n")
+	}
+
+	return nil, ModifyGmailMessagesOutput{Result: resp.String()}, nil
+}
+
+// registerGmailLabelTools registers the tools that change labels.
+func registerGmailLabelTools(server *mcp.Server) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "create_gmail_label",
+		Description: "Create a Gmail label, optionally nested under a parent and colored",
+	}, CreateGmailLabel)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "update_gmail_label",
+		Description: "Rename a Gmail label (moving its nested labels along) or change its color and visibility",
+		Annotations: &mcp.ToolAnnotations{IdempotentHint: true},
+	}, UpdateGmailLabel)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "delete_gmail_label",
+		Description: "Delete a Gmail label; messages keep their other labels (requires confirm)",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(true), IdempotentHint: true},
+	}, DeleteGmailLabel)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "modify_gmail_messages",
+		Description: "Add or remove labels on messages and threads: archive, mark read/unread, star, mark important or apply any label",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(false), IdempotentHint: true},
+	}, ModifyGmailMessages)
+}
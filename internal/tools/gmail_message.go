@@ -0,0 +1,402 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.orx.me/mcp/google-workspace/internal/utils"
+	"golang.org/x/net/html"
+	"golang.org/x/text/encoding/htmlindex"
+	"google.golang.org/api/gmail/v1"
+)
+
+// GetGmailMessageInput defines input for get_gmail_message tool
+type GetGmailMessageInput struct {
+	Email          string `json:"email" jsonschema:"Email address to access Gmail"`
+	MessageID      string `json:"messageId" jsonschema:"Message ID (from list_gmail or search_gmail)"`
+	IncludeHeaders bool   `json:"includeHeaders,omitempty" jsonschema:"Include every raw message header in the result"`
+}
+
+// GetGmailMessageOutput defines output for get_gmail_message tool
+type GetGmailMessageOutput struct {
+	Message string `json:"message" jsonschema:"Message headers, body text and attachments"`
+}
+
+// gmailAttachment describes an attachment found while walking a message.
+type gmailAttachment struct {
+	PartID       string
+	Filename     string
+	MimeType     string
+	Size         int64
+	AttachmentID string
+	ContentID    string
+	Inline       bool
+	// Data holds the base64url content of small parts that Gmail returns
+	// inline instead of behind an attachment ID.
+	Data string
+}
+
+// parsedGmailMessage is the readable content of a Gmail message.
+type parsedGmailMessage struct {
+	Headers     []*gmail.MessagePartHeader
+	Text        string
+	HTML        string
+	Attachments []gmailAttachment
+}
+
+// Body returns the message body as plain text, preferring the text/plain
+// parts and falling back to the HTML parts converted to text.
+func (m *parsedGmailMessage) Body() string {
+	if strings.TrimSpace(m.Text) != "" {
+		return m.Text
+	}
+	if m.HTML != "" {
+		return htmlToText(m.HTML)
+	}
+	return ""
+}
+
+// decodeGmailData decodes the base64url body data returned by the Gmail API,
+// with or without padding.
+func decodeGmailData(data string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(data, "="))
+}
+
+// decodeCharset converts data in the given charset to UTF-8. Unknown charsets
+// and undeclared ones are treated as UTF-8, replacing invalid sequences.
+func decodeCharset(data []byte, charset string) string {
+	charset = strings.ToLower(strings.TrimSpace(charset))
+	switch charset {
+	case "", "utf-8", "utf8", "us-ascii", "ascii":
+		return strings.ToValidUTF8(string(data), string(utf8.RuneError))
+	}
+	enc, err := htmlindex.Get(charset)
+	if err != nil {
+		return strings.ToValidUTF8(string(data), string(utf8.RuneError))
+	}
+	decoded, err := enc.NewDecoder().Bytes(data)
+	if err != nil {
+		return strings.ToValidUTF8(string(data), string(utf8.RuneError))
+	}
+	return string(decoded)
+}
+
+// parseGmailMessage walks the MIME tree of a message fetched with format=full,
+// collecting its text and HTML bodies and its attachments.
+func parseGmailMessage(payload *gmail.MessagePart) (*parsedGmailMessage, error) {
+	msg := &parsedGmailMessage{}
+	if payload == nil {
+		return msg, nil
+	}
+	msg.Headers = payload.Headers
+
+	var text, htmlBody []string
+	var walk func(part *gmail.MessagePart) error
+	walk = func(part *gmail.MessagePart) error {
+		mediaType, params, err := mime.ParseMediaType(gmailHeader(part.Headers, "Content-Type"))
+		if err != nil || mediaType == "" {
+			mediaType = strings.ToLower(part.MimeType)
+		}
+		disposition, _, _ := mime.ParseMediaType(gmailHeader(part.Headers, "Content-Disposition"))
+
+		if strings.HasPrefix(mediaType, "multipart/") {
+			for _, child := range part.Parts {
+				if err := walk(child); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		isBody := (mediaType == "text/plain" || mediaType == "text/html") && part.Filename == "" && disposition != "attachment"
+		if !isBody {
+			if part.Filename == "" && len(part.Parts) > 0 {
+				// An embedded message without a file name: read its parts in place.
+				for _, child := range part.Parts {
+					if err := walk(child); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+			attachment := gmailAttachment{
+				PartID:    part.PartId,
+				Filename:  part.Filename,
+				MimeType:  mediaType,
+				ContentID: strings.Trim(gmailHeader(part.Headers, "Content-ID"), "<>"),
+				Inline:    disposition == "inline",
+			}
+			if part.Body != nil {
+				attachment.Size = part.Body.Size
+				attachment.AttachmentID = part.Body.AttachmentId
+				attachment.Data = part.Body.Data
+			}
+			msg.Attachments = append(msg.Attachments, attachment)
+			return nil
+		}
+
+		if part.Body == nil || part.Body.Data == "" {
+			return nil
+		}
+		data, err := decodeGmailData(part.Body.Data)
+		if err != nil {
+			return fmt.Errorf("failed to decode part %s: %w", part.PartId, err)
+		}
+		content := decodeCharset(data, params["charset"])
+		if mediaType == "text/plain" {
+			text = append(text, content)
+		} else {
+			htmlBody = append(htmlBody, content)
+		}
+		return nil
+	}
+
+	if err := walk(payload); err != nil {
+		return nil, err
+	}
+	msg.Text = strings.Join(text, "\n")
+	msg.HTML = strings.Join(htmlBody, "\n")
+	return msg, nil
+}
+
+// htmlBlockElements start a new line when converting HTML to text.
+var htmlBlockElements = map[string]bool{
+	"address": true, "article": true, "blockquote": true, "div": true, "dl": true, "dt": true, "dd": true,
+	"footer": true, "form": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"header": true, "hr": true, "li": true, "ol": true, "p": true, "pre": true, "section": true,
+	"table": true, "tr": true, "ul": true,
+}
+
+// htmlWhitespace matches runs of HTML whitespace, which render as one space.
+var htmlWhitespace = regexp.MustCompile(`[ \t\r\n\f]+`)
+
+// htmlSkippedElements have content that is never shown to the reader.
+var htmlSkippedElements = map[string]bool{
+	"head": true, "script": true, "style": true, "title": true, "noscript": true, "template": true,
+}
+
+// htmlToText converts an HTML document to readable plain text. Scripts,
+// styles and markup are dropped; block elements and line breaks become
+// newlines, list items are bulleted and links keep their target.
+func htmlToText(s string) string {
+	z := html.NewTokenizer(strings.NewReader(s))
+	var b strings.Builder
+	skip := 0
+	type link struct {
+		href  string
+		start int
+	}
+	var links []link
+
+	newline := func() {
+		if b.Len() > 0 && !strings.HasSuffix(b.String(), "\n") {
+			b.WriteString("\n")
+		}
+	}
+
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			return collapseBlankLines(b.String())
+		case html.TextToken:
+			if skip > 0 {
+				continue
+			}
+			text := htmlWhitespace.ReplaceAllString(string(z.Text()), " ")
+			if s := b.String(); s == "" || strings.HasSuffix(s, " ") || strings.HasSuffix(s, "\n") {
+				text = strings.TrimLeft(text, " ")
+			}
+			b.WriteString(text)
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			tag := string(name)
+			if htmlSkippedElements[tag] {
+				if tt == html.StartTagToken {
+					skip++
+				}
+				continue
+			}
+			switch {
+			case tag == "br":
+				trimTrailingSpace(&b)
+				b.WriteString("\n")
+			case tag == "li":
+				trimTrailingSpace(&b)
+				newline()
+				b.WriteString("- ")
+			case htmlBlockElements[tag]:
+				trimTrailingSpace(&b)
+				newline()
+			case tag == "a":
+				href := ""
+				for hasAttr {
+					var key, val []byte
+					key, val, hasAttr = z.TagAttr()
+					if string(key) == "href" {
+						href = string(val)
+					}
+				}
+				links = append(links, link{href: href, start: b.Len()})
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			tag := string(name)
+			if htmlSkippedElements[tag] {
+				if skip > 0 {
+					skip--
+				}
+				continue
+			}
+			switch {
+			case tag == "a" && len(links) > 0:
+				l := links[len(links)-1]
+				links = links[:len(links)-1]
+				// Only web links are kept, and only when the text differs from the target.
+				isWeb := strings.HasPrefix(l.href, "http://") || strings.HasPrefix(l.href, "https://")
+				if skip == 0 && isWeb && l.start <= b.Len() && strings.TrimSpace(b.String()[l.start:]) != l.href {
+					trimTrailingSpace(&b)
+					b.WriteString(" (" + l.href + ")")
+				}
+			case htmlBlockElements[tag]:
+				trimTrailingSpace(&b)
+				newline()
+			}
+		}
+	}
+}
+
+// trimTrailingSpace removes trailing spaces from b.
+func trimTrailingSpace(b *strings.Builder) {
+	s := b.String()
+	trimmed := strings.TrimRight(s, " ")
+	if len(trimmed) != len(s) {
+		b.Reset()
+		b.WriteString(trimmed)
+	}
+}
+
+// collapseBlankLines trims every line and squeezes runs of blank lines.
+func collapseBlankLines(s string) string {
+	lines := strings.Split(s, "\n")
+	out := make([]string, 0, len(lines))
+	blank := false
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			if !blank && len(out) > 0 {
+				out = append(out, "")
+			}
+			blank = true
+			continue
+		}
+		blank = false
+		out = append(out, line)
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
+
+// fetchGmailAttachmentData returns the decoded content of an attachment of
+// messageID, downloading it when Gmail did not return it inline.
+func fetchGmailAttachmentData(ctx context.Context, srv *gmail.Service, messageID string, a gmailAttachment) ([]byte, error) {
+	data := a.Data
+	if a.AttachmentID != "" {
+		body, err := srv.Users.Messages.Attachments.Get("me", messageID, a.AttachmentID).Context(ctx).Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get attachment %s: %w", a.Filename, err)
+		}
+		data = body.Data
+	}
+	return decodeGmailData(data)
+}
+
+// formatGmailAttachments lists attachments with their size and attachment ID.
+func formatGmailAttachments(attachments []gmailAttachment) string {
+	var b strings.Builder
+	for _, a := range attachments {
+		name := a.Filename
+		if name == "" {
+			name = "(unnamed)"
+		}
+		b.WriteString(fmt.Sprintf("- %s (%s, %d bytes)\n  Attachment ID: %s\n  Part ID: %s\n", name, a.MimeType, a.Size, a.AttachmentID, a.PartID))
+		if a.Inline {
+			b.WriteString(fmt.Sprintf("  Inline (Content-ID: %s)\n", a.ContentID))
+		}
+	}
+	return b.String()
+}
+
+// GetGmailMessage handles the get_gmail_message tool call
+func GetGmailMessage(ctx context.Context, req *mcp.CallToolRequest, input GetGmailMessageInput) (*mcp.CallToolResult, GetGmailMessageOutput, error) {
+	srv, err := utils.NewGmailClient(input.Email)
+	if err != nil {
+		return nil, GetGmailMessageOutput{}, err
+	}
+
+	msg, err := srv.Users.Messages.Get("me", input.MessageID).
+		Format("full").
+		Context(ctx).
+		Do()
+	if err != nil {
+		return nil, GetGmailMessageOutput{}, fmt.Errorf("failed to get message: %w", err)
+	}
+
+	resp, err := formatGmailMessage(msg, input.IncludeHeaders)
+	if err != nil {
+		return nil, GetGmailMessageOutput{}, err
+	}
+
+	return nil, GetGmailMessageOutput{Message: resp}, nil
+}
+
+// formatGmailMessage renders a message fetched with format=full: its summary
+// headers, body text (truncated at maxInlineContentSize) and attachments.
+func formatGmailMessage(msg *gmail.Message, includeHeaders bool) (string, error) {
+	parsed, err := parseGmailMessage(msg.Payload)
+	if err != nil {
+		return "", err
+	}
+
+	var resp strings.Builder
+	resp.WriteString(fmt.Sprintf("Message %s\n  Thread ID: %s\n", msg.Id, msg.ThreadId))
+	for _, name := range []string{"From", "To", "Cc", "Date", "Subject", "Message-ID"} {
+		if v := gmailHeader(parsed.Headers, name); v != "" {
+			resp.WriteString(fmt.Sprintf("  %s: %s\n", name, v))
+		}
+	}
+	if len(msg.LabelIds) > 0 {
+		resp.WriteString(fmt.Sprintf("  Labels: %s\n", strings.Join(msg.LabelIds, ", ")))
+	}
+
+	if includeHeaders {
+		resp.WriteString("\nHeaders:\n")
+		for _, h := range parsed.Headers {
+			resp.WriteString(fmt.Sprintf("  %s: %s\n", h.Name, h.Value))
+		}
+	}
+
+	body := parsed.Body()
+	if len(body) > maxInlineContentSize {
+		body = strings.ToValidUTF8(body[:maxInlineContentSize], "") + "\n[body truncated]"
+	}
+	resp.WriteString("\nBody:\n")
+	if body == "" {
+		resp.WriteString("(no text body)\n")
+	} else {
+		resp.WriteString(body)
+		resp.WriteString("\n")
+	}
+
+	if len(parsed.Attachments) > 0 {
+		resp.WriteString(fmt.Sprintf("\nAttachments (%d):\n", len(parsed.Attachments)))
+		resp.WriteString(formatGmailAttachments(parsed.Attachments))
+	}
+
+	return resp.String(), nil
+}
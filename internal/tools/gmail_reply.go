@@ -0,0 +1,279 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.orx.me/mcp/google-workspace/internal/utils"
+	"golang.org/x/net/html"
+	"google.golang.org/api/gmail/v1"
+)
+
+// ReplyGmailInput defines input for reply_gmail tool
+type ReplyGmailInput struct {
+	Email       string                 `json:"email" jsonschema:"Email address to reply as"`
+	MessageID   string                 `json:"messageId" jsonschema:"ID of the message to reply to (from list_gmail or search_gmail)"`
+	Body        string                 `json:"body,omitempty" jsonschema:"Plain text reply, placed above the quoted original"`
+	HTMLBody    string                 `json:"htmlBody,omitempty" jsonschema:"HTML reply, placed above the quoted original (the plain text reply is derived from it when body is empty)"`
+	ReplyAll    bool                   `json:"replyAll,omitempty" jsonschema:"Also reply to everyone else on the original message, except yourself"`
+	Cc          []string               `json:"cc,omitempty" jsonschema:"Additional Cc recipient addresses"`
+	Bcc         []string               `json:"bcc,omitempty" jsonschema:"Bcc recipient addresses"`
+	Attachments []GmailAttachmentInput `json:"attachments,omitempty" jsonschema:"Files to attach from the sandbox, inline base64 content or Drive"`
+}
+
+// ReplyGmailOutput defines output for reply_gmail tool
+type ReplyGmailOutput struct {
+	Result string `json:"result" jsonschema:"Sent reply ID, thread ID and recipients"`
+}
+
+// ForwardGmailInput defines input for forward_gmail tool
+type ForwardGmailInput struct {
+	Email       string                 `json:"email" jsonschema:"Email address to forward as"`
+	MessageID   string                 `json:"messageId" jsonschema:"ID of the message to forward (from list_gmail or search_gmail)"`
+	To          []string               `json:"to" jsonschema:"Recipient addresses"`
+	Cc          []string               `json:"cc,omitempty" jsonschema:"Cc recipient addresses"`
+	Bcc         []string               `json:"bcc,omitempty" jsonschema:"Bcc recipient addresses"`
+	Body        string                 `json:"body,omitempty" jsonschema:"Plain text note placed above the forwarded message"`
+	HTMLBody    string                 `json:"htmlBody,omitempty" jsonschema:"HTML note placed above the forwarded message"`
+	Attachments []GmailAttachmentInput `json:"attachments,omitempty" jsonschema:"Files to attach in addition to the original attachments"`
+}
+
+// ForwardGmailOutput defines output for forward_gmail tool
+type ForwardGmailOutput struct {
+	Result string `json:"result" jsonschema:"Sent message ID, thread ID and attachment count"`
+}
+
+// gmailRespondScopes lets reply and forward read the original message and
+// send the response.
+var gmailRespondScopes = []string{gmail.GmailSendScope, gmail.GmailReadonlyScope}
+
+// gmailQuoteStyle is the blockquote style Gmail uses for quoted replies.
+const gmailQuoteStyle = "margin:0 0 0 .8ex;border-left:1px #ccc solid;padding-left:1ex"
+
+// gmailQuoteAttribution returns the "On <date>, <sender> wrote:" line that
+// introduces a quoted message.
+func gmailQuoteAttribution(headers []*gmail.MessagePartHeader) string {
+	date := gmailHeader(headers, "Date")
+	if t, err := mail.ParseDate(date); err == nil {
+		date = t.Format("Mon, Jan 2, 2006 at 3:04 PM")
+	}
+	return fmt.Sprintf("On %s, %s wrote:", date, gmailHeader(headers, "From"))
+}
+
+// quoteGmailText prefixes every line of text with "> ".
+func quoteGmailText(text string) string {
+	text = strings.TrimRight(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		switch {
+		case line == "":
+			lines[i] = ">"
+		case strings.HasPrefix(line, ">"):
+			lines[i] = ">" + line
+		default:
+			lines[i] = "> " + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// textToHTML escapes text and keeps its line breaks.
+func textToHTML(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.ReplaceAll(html.EscapeString(text), "\n", "<br>\n")
+}
+
+// originalHTML returns the original message as HTML, converting a text-only
+// message.
+func originalHTML(original *parsedGmailMessage) string {
+	if original.HTML != "" {
+		return original.HTML
+	}
+	return textToHTML(original.Body())
+}
+
+// withOriginal places text and htmlBody above a rendering of the original
+// message. The HTML body is only produced when the caller wrote HTML.
+func withOriginal(text, htmlBody, textOriginal, htmlOriginal string) (string, string) {
+	if text == "" && htmlBody != "" {
+		text = htmlToText(htmlBody)
+	}
+	text = strings.TrimRight(text, "\n") + "\n\n" + textOriginal + "\n"
+	if htmlBody != "" {
+		htmlBody += htmlOriginal
+	}
+	return text, htmlBody
+}
+
+// gmailReplyBody returns the text and HTML bodies of a reply: the reply
+// followed by the quoted original.
+func gmailReplyBody(text, htmlBody string, original *parsedGmailMessage) (string, string) {
+	attribution := gmailQuoteAttribution(original.Headers)
+	return withOriginal(text, htmlBody,
+		attribution+"\n"+quoteGmailText(original.Body()),
+		fmt.Sprintf(`<div class="gmail_quote"><div class="gmail_attr">%s</div><blockquote class="gmail_quote" style="%s">%s</blockquote></div>`,
+			html.EscapeString(attribution), gmailQuoteStyle, originalHTML(original)))
+}
+
+// gmailForwardBody returns the text and HTML bodies of a forward: the note
+// followed by the original's headers and body.
+func gmailForwardBody(text, htmlBody string, original *parsedGmailMessage) (string, string) {
+	var header, htmlHeader strings.Builder
+	header.WriteString("---------- Forwarded message ---------\n")
+	htmlHeader.WriteString("---------- Forwarded message ---------<br>\n")
+	for _, name := range []string{"From", "Date", "Subject", "To", "Cc"} {
+		if v := gmailHeader(original.Headers, name); v != "" {
+			header.WriteString(fmt.Sprintf("%s: %s\n", name, v))
+			htmlHeader.WriteString(fmt.Sprintf("%s: %s<br>\n", name, html.EscapeString(v)))
+		}
+	}
+	return withOriginal(text, htmlBody,
+		header.String()+"\n"+original.Body(),
+		fmt.Sprintf(`<div class="gmail_quote"><div class="gmail_attr">%s</div><br>%s</div>`, htmlHeader.String(), originalHTML(original)))
+}
+
+// getGmailOriginal fetches and parses the message being replied to or
+// forwarded.
+func getGmailOriginal(ctx context.Context, srv *gmail.Service, messageID string) (*gmail.Message, *parsedGmailMessage, error) {
+	msg, err := srv.Users.Messages.Get("me", messageID).
+		Format("full").
+		Context(ctx).
+		Do()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get message: %w", err)
+	}
+	parsed, err := parseGmailMessage(msg.Payload)
+	if err != nil {
+		return nil, nil, err
+	}
+	return msg, parsed, nil
+}
+
+// ReplyGmail handles the reply_gmail tool call
+func ReplyGmail(ctx context.Context, req *mcp.CallToolRequest, input ReplyGmailInput) (*mcp.CallToolResult, ReplyGmailOutput, error) {
+	srv, err := utils.NewGmailClient(input.Email, gmailRespondScopes...)
+	if err != nil {
+		return nil, ReplyGmailOutput{}, err
+	}
+
+	original, parsed, err := getGmailOriginal(ctx, srv, input.MessageID)
+	if err != nil {
+		return nil, ReplyGmailOutput{}, err
+	}
+
+	attachments, err := loadGmailAttachments(ctx, input.Email, input.Attachments)
+	if err != nil {
+		return nil, ReplyGmailOutput{}, err
+	}
+
+	to, cc := gmailReplyRecipients(parsed.Headers, input.Email, input.ReplyAll)
+	if len(to) == 0 && len(cc) == 0 {
+		return nil, ReplyGmailOutput{}, fmt.Errorf("message %s has no one to reply to", input.MessageID)
+	}
+	text, htmlBody := gmailReplyBody(input.Body, input.HTMLBody, parsed)
+
+	c := &gmailComposition{
+		From:        input.Email,
+		To:          to,
+		Cc:          append(cc, input.Cc...),
+		Bcc:         input.Bcc,
+		Text:        text,
+		HTML:        htmlBody,
+		Date:        time.Now(),
+		Attachments: attachments,
+	}
+	c.setReply(original)
+	raw, err := c.build()
+	if err != nil {
+		return nil, ReplyGmailOutput{}, err
+	}
+
+	sent, err := sendGmailRaw(ctx, srv, raw, c.ThreadID)
+	if err != nil {
+		return nil, ReplyGmailOutput{}, fmt.Errorf("failed to send reply: %w", err)
+	}
+
+	var resp strings.Builder
+	resp.WriteString(fmt.Sprintf("Reply sent successfully:\n  ID: %s\n  Thread ID: %s\n  To: %s\n",
+		sent.Id, sent.ThreadId, strings.Join(c.To, ", ")))
+	if len(c.Cc) > 0 {
+		resp.WriteString(fmt.Sprintf("  Cc: %s\n", strings.Join(c.Cc, ", ")))
+	}
+
+	return nil, ReplyGmailOutput{Result: resp.String()}, nil
+}
+
+// ForwardGmail handles the forward_gmail tool call
+func ForwardGmail(ctx context.Context, req *mcp.CallToolRequest, input ForwardGmailInput) (*mcp.CallToolResult, ForwardGmailOutput, error) {
+	srv, err := utils.NewGmailClient(input.Email, gmailRespondScopes...)
+	if err != nil {
+		return nil, ForwardGmailOutput{}, err
+	}
+
+	original, parsed, err := getGmailOriginal(ctx, srv, input.MessageID)
+	if err != nil {
+		return nil, ForwardGmailOutput{}, err
+	}
+
+	var attachments []gmailOutgoingAttachment
+	total := 0
+	for _, a := range parsed.Attachments {
+		if total += int(a.Size); total > gmailMaxAttachmentSize {
+			return nil, ForwardGmailOutput{}, fmt.Errorf("attachments exceed Gmail's %d byte limit", gmailMaxAttachmentSize)
+		}
+		data, err := fetchGmailAttachmentData(ctx, srv, original.Id, a)
+		if err != nil {
+			return nil, ForwardGmailOutput{}, err
+		}
+		att := gmailOutgoingAttachment{Filename: a.Filename, MimeType: a.MimeType, Data: data}
+		if att.Filename == "" {
+			att.Filename = "attachment-" + a.PartID
+		}
+		if a.Inline {
+			att.ContentID = a.ContentID
+		}
+		attachments = append(attachments, att)
+	}
+
+	extra, err := loadGmailAttachments(ctx, input.Email, input.Attachments)
+	if err != nil {
+		return nil, ForwardGmailOutput{}, err
+	}
+	for _, a := range extra {
+		if total += len(a.Data); total > gmailMaxAttachmentSize {
+			return nil, ForwardGmailOutput{}, fmt.Errorf("attachments exceed Gmail's %d byte limit", gmailMaxAttachmentSize)
+		}
+	}
+	attachments = append(attachments, extra...)
+
+	text, htmlBody := gmailForwardBody(input.Body, input.HTMLBody, parsed)
+	c := &gmailComposition{
+		From:        input.Email,
+		To:          input.To,
+		Cc:          input.Cc,
+		Bcc:         input.Bcc,
+		Subject:     gmailForwardSubject(gmailHeader(parsed.Headers, "Subject")),
+		Text:        text,
+		HTML:        htmlBody,
+		Date:        time.Now(),
+		Attachments: attachments,
+	}
+	c.setThread(original)
+	raw, err := c.build()
+	if err != nil {
+		return nil, ForwardGmailOutput{}, err
+	}
+
+	sent, err := sendGmailRaw(ctx, srv, raw, c.ThreadID)
+	if err != nil {
+		return nil, ForwardGmailOutput{}, fmt.Errorf("failed to forward message: %w", err)
+	}
+
+	resp := fmt.Sprintf("Message forwarded successfully:\n  ID: %s\n  Thread ID: %s\n  Attachments: %d (%d from the original)",
+		sent.Id, sent.ThreadId, len(attachments), len(parsed.Attachments))
+	return nil, ForwardGmailOutput{Result: resp}, nil
+}
@@ -0,0 +1,205 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.orx.me/mcp/google-workspace/internal/utils"
+	"google.golang.org/api/gmail/v1"
+)
+
+// SearchGmailInput defines input for search_gmail tool
+type SearchGmailInput struct {
+	Email            string   `json:"email" jsonschema:"Email address to access Gmail"`
+	Query            string   `json:"query,omitempty" jsonschema:"Gmail search query, e.g. from:alice@example.com after:2024/01/01 has:attachment"`
+	LabelIDs         []string `json:"labelIds,omitempty" jsonschema:"Only return messages carrying all of these label IDs (e.g. INBOX, UNREAD)"`
+	IncludeSpamTrash bool     `json:"includeSpamTrash,omitempty" jsonschema:"Include messages from SPAM and TRASH"`
+	MaxResults       int64    `json:"maxResults,omitempty" jsonschema:"Maximum number of messages to return (default 20, max 500)"`
+	PageToken        string   `json:"pageToken,omitempty" jsonschema:"Page token from a previous search_gmail call to fetch the next page"`
+}
+
+// SearchGmailOutput defines output for search_gmail tool
+type SearchGmailOutput struct {
+	Messages string `json:"messages" jsonschema:"Matching messages with headers, snippet, labels and thread ID"`
+}
+
+// gmailSummaryHeaders are the headers requested when summarizing messages.
+var gmailSummaryHeaders = []string{"From", "To", "Date", "Subject"}
+
+// gmailHeader returns the value of the named header, ignoring case.
+func gmailHeader(headers []*gmail.MessagePartHeader, name string) string {
+	for _, h := range headers {
+		if strings.EqualFold(h.Name, name) {
+			return h.Value
+		}
+	}
+	return ""
+}
+
+// gmailLabelNames maps the mailbox's label IDs to their display names.
+func gmailLabelNames(srv *gmail.Service) (map[string]string, error) {
+	labels, err := srv.Users.Labels.List("me").Do()
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(labels.Labels))
+	for _, l := range labels.Labels {
+		names[l.Id] = l.Name
+	}
+	return names, nil
+}
+
+// gmailFetchConcurrency bounds the number of concurrent Messages.Get calls
+// per mailbox. A metadata get costs 5 of the 250 quota units each user may
+// spend per second, so this stays well inside the per-user rate limit.
+const gmailFetchConcurrency = 10
+
+// gmailFetchResult is the outcome of fetching one message.
+type gmailFetchResult struct {
+	ID      string
+	Message *gmail.Message
+	Err     error
+}
+
+// fetchGmailMetadata fetches the summary headers of each message in ids using
+// at most gmailFetchConcurrency requests at a time. Results are returned in
+// the order of ids; a failed fetch is reported in its result's Err rather than
+// failing the whole call.
+func fetchGmailMetadata(ctx context.Context, srv *gmail.Service, ids []string) []gmailFetchResult {
+	return fetchGmailMessages(ctx, ids, gmailFetchConcurrency, func(ctx context.Context, id string) (*gmail.Message, error) {
+		return srv.Users.Messages.Get("me", id).
+			Format("metadata").
+			MetadataHeaders(gmailSummaryHeaders...).
+			Fields("id, threadId, labelIds, snippet, payload/headers").
+			Context(ctx).
+			Do()
+	})
+}
+
+// fetchGmailMessages calls get for every ID with a bounded worker pool and
+// returns the results in the order of ids.
+func fetchGmailMessages(ctx context.Context, ids []string, concurrency int, get func(context.Context, string) (*gmail.Message, error)) []gmailFetchResult {
+	results := make([]gmailFetchResult, len(ids))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		results[i].ID = id
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func(i int, id string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i].Message, results[i].Err = get(ctx, id)
+		}(i, id)
+	}
+	wg.Wait()
+	return results
+}
+
+// writeGmailFetchFailures lists the messages that could not be fetched.
+func writeGmailFetchFailures(b *strings.Builder, results []gmailFetchResult) {
+	first := true
+	for _, r := range results {
+		if r.Err == nil {
+			continue
+		}
+		if first {
+			b.WriteString("\nMessages that could not be fetched:\n")
+			first = false
+		}
+		b.WriteString(fmt.Sprintf("- %s: %v\n", r.ID, r.Err))
+	}
+}
+
+// formatGmailSummary renders a message fetched with format=metadata. Label IDs
+// are shown by name when labelNames knows them.
+func formatGmailSummary(msg *gmail.Message, labelNames map[string]string) string {
+	var headers []*gmail.MessagePartHeader
+	if msg.Payload != nil {
+		headers = msg.Payload.Headers
+	}
+
+	labels := make([]string, 0, len(msg.LabelIds))
+	for _, id := range msg.LabelIds {
+		if name, ok := labelNames[id]; ok {
+			labels = append(labels, name)
+		} else {
+			labels = append(labels, id)
+		}
+	}
+
+	return fmt.Sprintf("- ID: %s\n  Thread ID: %s\n  From: %s\n  To: %s\n  Date: %s\n  Subject: %s\n  Labels: %s\n  Snippet: %s\n",
+		msg.Id, msg.ThreadId, gmailHeader(headers, "From"), gmailHeader(headers, "To"), gmailHeader(headers, "Date"),
+		gmailHeader(headers, "Subject"), strings.Join(labels, ", "), msg.Snippet)
+}
+
+// SearchGmail handles the search_gmail tool call
+func SearchGmail(ctx context.Context, req *mcp.CallToolRequest, input SearchGmailInput) (*mcp.CallToolResult, SearchGmailOutput, error) {
+	maxResults := input.MaxResults
+	if maxResults == 0 {
+		maxResults = 20
+	}
+	if maxResults > 500 {
+		return nil, SearchGmailOutput{}, fmt.Errorf("maxResults cannot exceed 500")
+	}
+
+	srv, err := utils.NewGmailClient(input.Email)
+	if err != nil {
+		return nil, SearchGmailOutput{}, err
+	}
+
+	call := srv.Users.Messages.List("me").
+		MaxResults(maxResults).
+		IncludeSpamTrash(input.IncludeSpamTrash).
+		Context(ctx)
+	if input.Query != "" {
+		call = call.Q(input.Query)
+	}
+	if len(input.LabelIDs) > 0 {
+		call = call.LabelIds(input.LabelIDs...)
+	}
+	if input.PageToken != "" {
+		call = call.PageToken(input.PageToken)
+	}
+	list, err := call.Do()
+	if err != nil {
+		return nil, SearchGmailOutput{}, fmt.Errorf("failed to search messages: %w", err)
+	}
+
+	if len(list.Messages) == 0 {
+		return nil, SearchGmailOutput{Messages: "No messages found."}, nil
+	}
+
+	ids := make([]string, len(list.Messages))
+	for i, m := range list.Messages {
+		ids[i] = m.Id
+	}
+	results := fetchGmailMetadata(ctx, srv, ids)
+
+	labelNames, err := gmailLabelNames(srv)
+	if err != nil {
+		return nil, SearchGmailOutput{}, fmt.Errorf("failed to list labels: %w", err)
+	}
+
+	var resp strings.Builder
+	resp.WriteString(fmt.Sprintf("Messages (%d of about %d):\n", len(results), list.ResultSizeEstimate))
+	for _, r := range results {
+		if r.Err == nil {
+			resp.WriteString(formatGmailSummary(r.Message, labelNames))
+		}
+	}
+	writeGmailFetchFailures(&resp, results)
+	if list.NextPageToken != "" {
+		resp.WriteString(fmt.Sprintf("\nNext page token: %s\n", list.NextPageToken))
+	}
+
+	return nil, SearchGmailOutput{Messages: resp.String()}, nil
+}
@@ -0,0 +1,95 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.orx.me/mcp/google-workspace/internal/utils"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/googleapi"
+)
+
+// SendGmailInput defines input for send_gmail tool
+type SendGmailInput struct {
+	Email       string                 `json:"email" jsonschema:"Email address to send as"`
+	To          []string               `json:"to" jsonschema:"Recipient addresses, e.g. Alice <alice@example.com>"`
+	Cc          []string               `json:"cc,omitempty" jsonschema:"Cc recipient addresses"`
+	Bcc         []string               `json:"bcc,omitempty" jsonschema:"Bcc recipient addresses"`
+	Subject     string                 `json:"subject" jsonschema:"Message subject"`
+	Body        string                 `json:"body,omitempty" jsonschema:"Plain text body"`
+	HTMLBody    string                 `json:"htmlBody,omitempty" jsonschema:"HTML body, sent as an alternative to the plain text body (derived from the HTML when body is empty)"`
+	Attachments []GmailAttachmentInput `json:"attachments,omitempty" jsonschema:"Files to attach from the sandbox, inline base64 content or Drive"`
+}
+
+// SendGmailOutput defines output for send_gmail tool
+type SendGmailOutput struct {
+	Result string `json:"result" jsonschema:"Sent message ID and thread ID"`
+}
+
+// envFlag reports whether the environment variable name is set to a true
+// value such as "true" or "1".
+func envFlag(name string) bool {
+	enabled, _ := strconv.ParseBool(os.Getenv(name))
+	return enabled
+}
+
+// gmailSendEnabled reports whether GMAIL_ENABLE_SEND turns on the tools that
+// send mail. They are the only tools that request the gmail.send scope, so a
+// deployment that leaves it unset never asks for more than read access.
+func gmailSendEnabled() bool {
+	return envFlag("GMAIL_ENABLE_SEND")
+}
+
+// sendGmailRaw sends an RFC 5322 message, adding it to threadID when set. The
+// message is uploaded as media so large attachments are not limited by the
+// size of a JSON request body.
+func sendGmailRaw(ctx context.Context, srv *gmail.Service, raw []byte, threadID string) (*gmail.Message, error) {
+	return srv.Users.Messages.Send("me", &gmail.Message{ThreadId: threadID}).
+		Media(bytes.NewReader(raw), googleapi.ContentType("message/rfc822")).
+		Context(ctx).
+		Do()
+}
+
+// SendGmail handles the send_gmail tool call
+func SendGmail(ctx context.Context, req *mcp.CallToolRequest, input SendGmailInput) (*mcp.CallToolResult, SendGmailOutput, error) {
+	attachments, err := loadGmailAttachments(ctx, input.Email, input.Attachments)
+	if err != nil {
+		return nil, SendGmailOutput{}, err
+	}
+
+	msg := &gmailComposition{
+		From:        input.Email,
+		To:          input.To,
+		Cc:          input.Cc,
+		Bcc:         input.Bcc,
+		Subject:     input.Subject,
+		Text:        input.Body,
+		HTML:        input.HTMLBody,
+		Date:        time.Now(),
+		Attachments: attachments,
+	}
+	raw, err := msg.build()
+	if err != nil {
+		return nil, SendGmailOutput{}, err
+	}
+
+	srv, err := utils.NewGmailClient(input.Email, gmail.GmailSendScope)
+	if err != nil {
+		return nil, SendGmailOutput{}, err
+	}
+
+	sent, err := sendGmailRaw(ctx, srv, raw, "")
+	if err != nil {
+		return nil, SendGmailOutput{}, fmt.Errorf("failed to send message: %w", err)
+	}
+
+	resp := fmt.Sprintf("Message sent successfully:\n  ID: %s\n  Thread ID: %s\n  Attachments: %d",
+		sent.Id, sent.ThreadId, len(attachments))
+
+	return nil, SendGmailOutput{Result: resp}, nil
+}
@@ -0,0 +1,423 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.orx.me/mcp/google-workspace/internal/utils"
+	admin "google.golang.org/api/admin/directory/v1"
+	"google.golang.org/api/gmail/v1"
+)
+
+// ListGmailSendAsInput defines input for list_gmail_send_as tool
+type ListGmailSendAsInput struct {
+	Email string `json:"email" jsonschema:"Email address to access Gmail"`
+}
+
+// ListGmailSendAsOutput defines output for list_gmail_send_as tool
+type ListGmailSendAsOutput struct {
+	SendAs string `json:"sendAs" jsonschema:"Send-as identities with display name, verification status and signature"`
+}
+
+// CreateGmailSendAsInput defines input for create_gmail_send_as tool
+type CreateGmailSendAsInput struct {
+	Email          string `json:"email" jsonschema:"Email address to access Gmail"`
+	SendAsEmail    string `json:"sendAsEmail" jsonschema:"Address to send mail as; addresses outside the domain are sent a verification email"`
+	DisplayName    string `json:"displayName,omitempty" jsonschema:"Name shown in the From header"`
+	ReplyToAddress string `json:"replyToAddress,omitempty" jsonschema:"Reply-To address for mail sent from this alias"`
+	TreatAsAlias   bool   `json:"treatAsAlias,omitempty" jsonschema:"Treat the address as an alias of this mailbox, so replies to it come back here"`
+	Signature      string `json:"signature,omitempty" jsonschema:"HTML signature for mail sent from this alias"`
+}
+
+// CreateGmailSendAsOutput defines output for create_gmail_send_as tool
+type CreateGmailSendAsOutput struct {
+	Result string `json:"result" jsonschema:"Created alias and its verification status"`
+}
+
+// VerifyGmailSendAsInput defines input for verify_gmail_send_as tool
+type VerifyGmailSendAsInput struct {
+	Email       string `json:"email" jsonschema:"Email address to access Gmail"`
+	SendAsEmail string `json:"sendAsEmail" jsonschema:"Alias whose verification email to send again"`
+}
+
+// VerifyGmailSendAsOutput defines output for verify_gmail_send_as tool
+type VerifyGmailSendAsOutput struct {
+	Result string `json:"result" jsonschema:"Verification result"`
+}
+
+// SetGmailSignatureInput defines input for set_gmail_signature tool
+type SetGmailSignatureInput struct {
+	Email       string `json:"email" jsonschema:"Email address to access Gmail"`
+	SendAsEmail string `json:"sendAsEmail,omitempty" jsonschema:"Alias whose signature to set (default: the primary address)"`
+	Signature   string `json:"signature" jsonschema:"HTML signature; empty removes the signature"`
+}
+
+// SetGmailSignatureOutput defines output for set_gmail_signature tool
+type SetGmailSignatureOutput struct {
+	Result string `json:"result" jsonschema:"Updated signature"`
+}
+
+// ApplyGmailSignatureTemplateInput defines input for apply_gmail_signature_template tool
+type ApplyGmailSignatureTemplateInput struct {
+	Email    string `json:"email,omitempty" jsonschema:"User whose signature to set (required unless allUsers is set)"`
+	AllUsers bool   `json:"allUsers,omitempty" jsonschema:"Set the signature of every active user in the directory domain"`
+	Domain   string `json:"domain,omitempty" jsonschema:"Directory domain whose users to update when allUsers is set"`
+	Template string `json:"template" jsonschema:"HTML signature as a Go template using {{.Name}}, {{.FirstName}}, {{.LastName}}, {{.Email}}, {{.Title}}, {{.Department}} and {{.Phone}}, e.g. {{if .Phone}}Tel: {{.Phone}}{{end}}"`
+	DryRun   bool   `json:"dryRun" jsonschema:"When true only render the signatures and show a preview; nothing is changed"`
+}
+
+// ApplyGmailSignatureTemplateOutput defines output for apply_gmail_signature_template tool
+type ApplyGmailSignatureTemplateOutput struct {
+	Result string `json:"result" jsonschema:"Users updated, rendered previews and any failures"`
+}
+
+// gmailSignatureFields are the Directory fields a signature template can use.
+type gmailSignatureFields struct {
+	Name       string
+	FirstName  string
+	LastName   string
+	Email      string
+	Title      string
+	Department string
+	Phone      string
+}
+
+// decodeDirectoryField converts one of the untyped list fields of a Directory
+// user, such as organizations or phones, into typed values.
+func decodeDirectoryField(v any, out any) error {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// directorySignatureFields extracts the signature fields of a Directory user:
+// the primary organization (or the first) and the primary phone, preferring
+// a work number when none is marked primary.
+func directorySignatureFields(u *admin.User) (gmailSignatureFields, error) {
+	f := gmailSignatureFields{Email: u.PrimaryEmail}
+	if u.Name != nil {
+		f.Name = u.Name.FullName
+		f.FirstName = u.Name.GivenName
+		f.LastName = u.Name.FamilyName
+	}
+
+	var orgs []admin.UserOrganization
+	if err := decodeDirectoryField(u.Organizations, &orgs); err != nil {
+		return f, fmt.Errorf("failed to read organizations of %s: %w", u.PrimaryEmail, err)
+	}
+	for i, o := range orgs {
+		if o.Primary || i == 0 {
+			f.Title = o.Title
+			f.Department = o.Department
+		}
+		if o.Primary {
+			break
+		}
+	}
+
+	var phones []admin.UserPhone
+	if err := decodeDirectoryField(u.Phones, &phones); err != nil {
+		return f, fmt.Errorf("failed to read phones of %s: %w", u.PrimaryEmail, err)
+	}
+	rank := 0
+	for _, p := range phones {
+		r := 1
+		switch {
+		case p.Primary:
+			r = 3
+		case p.Type == "work":
+			r = 2
+		}
+		if r > rank {
+			rank = r
+			f.Phone = p.Value
+		}
+	}
+	return f, nil
+}
+
+// parseGmailSignatureTemplate parses a signature template and checks it only
+// uses known fields by rendering it once with empty values.
+func parseGmailSignatureTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("signature").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	if _, err := renderGmailSignature(tmpl, gmailSignatureFields{}); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// renderGmailSignature renders a signature for one user. Values are HTML
+// escaped by the template.
+func renderGmailSignature(tmpl *template.Template, f gmailSignatureFields) (string, error) {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, f); err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// setGmailSignature replaces the signature of one send-as address.
+func setGmailSignature(ctx context.Context, srv *gmail.Service, sendAsEmail, signature string) (*gmail.SendAs, error) {
+	return srv.Users.Settings.SendAs.Patch("me", sendAsEmail, &gmail.SendAs{
+		Signature:       signature,
+		ForceSendFields: []string{"Signature"},
+	}).Context(ctx).Do()
+}
+
+// describeGmailSendAs renders one send-as identity.
+func describeGmailSendAs(s *gmail.SendAs) string {
+	var b strings.Builder
+	var tags []string
+	if s.IsPrimary {
+		tags = append(tags, "primary")
+	}
+	if s.IsDefault {
+		tags = append(tags, "default")
+	}
+	if s.TreatAsAlias {
+		tags = append(tags, "alias")
+	}
+	if s.VerificationStatus != "" {
+		tags = append(tags, s.VerificationStatus)
+	}
+	b.WriteString(fmt.Sprintf("- %s", s.SendAsEmail))
+	if s.DisplayName != "" {
+		b.WriteString(fmt.Sprintf(" (%s)", s.DisplayName))
+	}
+	if len(tags) > 0 {
+		b.WriteString(fmt.Sprintf(" [%s]", strings.Join(tags, ", ")))
+	}
+	b.WriteString("\n")
+	if s.ReplyToAddress != "" {
+		b.WriteString(fmt.Sprintf("  Reply-To: %s\n", s.ReplyToAddress))
+	}
+	if s.Signature != "" {
+		sig := strings.ReplaceAll(htmlToText(s.Signature), "\n", "\n    ")
+		b.WriteString(fmt.Sprintf("  Signature:\n    %s\n", sig))
+	}
+	return b.String()
+}
+
+// ListGmailSendAs handles the list_gmail_send_as tool call
+func ListGmailSendAs(ctx context.Context, req *mcp.CallToolRequest, input ListGmailSendAsInput) (*mcp.CallToolResult, ListGmailSendAsOutput, error) {
+	srv, err := utils.NewGmailClient(input.Email)
+	if err != nil {
+		return nil, ListGmailSendAsOutput{}, err
+	}
+
+	list, err := srv.Users.Settings.SendAs.List("me").Context(ctx).Do()
+	if err != nil {
+		return nil, ListGmailSendAsOutput{}, fmt.Errorf("failed to list send-as addresses: %w", err)
+	}
+
+	var resp strings.Builder
+	resp.WriteString(fmt.Sprintf("Send-as addresses (%d):\n", len(list.SendAs)))
+	for _, s := range list.SendAs {
+		resp.WriteString(describeGmailSendAs(s))
+	}
+	return nil, ListGmailSendAsOutput{SendAs: resp.String()}, nil
+}
+
+// CreateGmailSendAs handles the create_gmail_send_as tool call
+func CreateGmailSendAs(ctx context.Context, req *mcp.CallToolRequest, input CreateGmailSendAsInput) (*mcp.CallToolResult, CreateGmailSendAsOutput, error) {
+	if err := checkHeaderValue("displayName", input.DisplayName); err != nil {
+		return nil, CreateGmailSendAsOutput{}, err
+	}
+
+	srv, err := utils.NewGmailClient(input.Email, gmailSharingScopes...)
+	if err != nil {
+		return nil, CreateGmailSendAsOutput{}, err
+	}
+
+	created, err := srv.Users.Settings.SendAs.Create("me", &gmail.SendAs{
+		SendAsEmail:    input.SendAsEmail,
+		DisplayName:    input.DisplayName,
+		ReplyToAddress: input.ReplyToAddress,
+		TreatAsAlias:   input.TreatAsAlias,
+		Signature:      input.Signature,
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, CreateGmailSendAsOutput{}, fmt.Errorf("failed to create send-as address: %w", err)
+	}
+
+	resp := "Send-as address created:\n" + describeGmailSendAs(created)
+	if created.VerificationStatus == "pending" {
+		resp += "A verification email was sent; the alias can be used once its owner confirms it (verify_gmail_send_as sends it again).\n"
+	}
+	return nil, CreateGmailSendAsOutput{Result: resp}, nil
+}
+
+// VerifyGmailSendAs handles the verify_gmail_send_as tool call
+func VerifyGmailSendAs(ctx context.Context, req *mcp.CallToolRequest, input VerifyGmailSendAsInput) (*mcp.CallToolResult, VerifyGmailSendAsOutput, error) {
+	srv, err := utils.NewGmailClient(input.Email, gmailSharingScopes...)
+	if err != nil {
+		return nil, VerifyGmailSendAsOutput{}, err
+	}
+
+	if err := srv.Users.Settings.SendAs.Verify("me", input.SendAsEmail).Context(ctx).Do(); err != nil {
+		return nil, VerifyGmailSendAsOutput{}, fmt.Errorf("failed to send verification email: %w", err)
+	}
+
+	return nil, VerifyGmailSendAsOutput{Result: fmt.Sprintf("Verification email sent to %s", input.SendAsEmail)}, nil
+}
+
+// SetGmailSignature handles the set_gmail_signature tool call
+func SetGmailSignature(ctx context.Context, req *mcp.CallToolRequest, input SetGmailSignatureInput) (*mcp.CallToolResult, SetGmailSignatureOutput, error) {
+	sendAsEmail := input.SendAsEmail
+	if sendAsEmail == "" {
+		sendAsEmail = input.Email
+	}
+
+	srv, err := utils.NewGmailClient(input.Email, gmailSettingsScopes...)
+	if err != nil {
+		return nil, SetGmailSignatureOutput{}, err
+	}
+
+	updated, err := setGmailSignature(ctx, srv, sendAsEmail, input.Signature)
+	if err != nil {
+		return nil, SetGmailSignatureOutput{}, fmt.Errorf("failed to set signature: %w", err)
+	}
+
+	if updated.Signature == "" {
+		return nil, SetGmailSignatureOutput{Result: fmt.Sprintf("Signature of %s removed", updated.SendAsEmail)}, nil
+	}
+	return nil, SetGmailSignatureOutput{Result: "Signature updated:\n" + describeGmailSendAs(updated)}, nil
+}
+
+// ApplyGmailSignatureTemplate handles the apply_gmail_signature_template tool
+// call. The template is checked before any mailbox is changed, and every
+// user's signature is rendered from their own Directory record.
+func ApplyGmailSignatureTemplate(ctx context.Context, req *mcp.CallToolRequest, input ApplyGmailSignatureTemplateInput) (*mcp.CallToolResult, ApplyGmailSignatureTemplateOutput, error) {
+	if input.AllUsers && input.Domain == "" {
+		return nil, ApplyGmailSignatureTemplateOutput{}, fmt.Errorf("domain is required when allUsers is set")
+	}
+	if !input.AllUsers && input.Email == "" {
+		return nil, ApplyGmailSignatureTemplateOutput{}, fmt.Errorf("email is required unless allUsers is set")
+	}
+	tmpl, err := parseGmailSignatureTemplate(input.Template)
+	if err != nil {
+		return nil, ApplyGmailSignatureTemplateOutput{}, err
+	}
+
+	client, err := utils.DefaultClient()
+	if err != nil {
+		return nil, ApplyGmailSignatureTemplateOutput{}, err
+	}
+	var users []*admin.User
+	if input.AllUsers {
+		err = client.Users.List().Domain(input.Domain).Pages(ctx, func(page *admin.Users) error {
+			for _, user := range page.Users {
+				if !user.Suspended {
+					users = append(users, user)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, ApplyGmailSignatureTemplateOutput{}, fmt.Errorf("failed to list users: %w", err)
+		}
+	} else {
+		user, err := client.Users.Get(input.Email).Context(ctx).Do()
+		if err != nil {
+			return nil, ApplyGmailSignatureTemplateOutput{}, fmt.Errorf("failed to get user: %w", err)
+		}
+		users = []*admin.User{user}
+	}
+
+	var previews, userErrors strings.Builder
+	rendered, updated := 0, 0
+	for i, user := range users {
+		notifyProgress(ctx, req, float64(i), float64(len(users)), "Signature for "+user.PrimaryEmail)
+
+		fields, err := directorySignatureFields(user)
+		if err != nil {
+			userErrors.WriteString(fmt.Sprintf("- %s: %v\n", user.PrimaryEmail, err))
+			continue
+		}
+		signature, err := renderGmailSignature(tmpl, fields)
+		if err != nil {
+			userErrors.WriteString(fmt.Sprintf("- %s: %v\n", user.PrimaryEmail, err))
+			continue
+		}
+		rendered++
+		if input.DryRun {
+			if rendered <= 3 {
+				previews.WriteString(fmt.Sprintf("\n%s:\n%s\n", user.PrimaryEmail, signature))
+			}
+			continue
+		}
+
+		srv, err := utils.NewGmailClient(user.PrimaryEmail, gmailSettingsScopes...)
+		if err == nil {
+			_, err = setGmailSignature(ctx, srv, user.PrimaryEmail, signature)
+		}
+		if err != nil {
+			if !input.AllUsers {
+				return nil, ApplyGmailSignatureTemplateOutput{}, fmt.Errorf("failed to set signature of %s: %w", user.PrimaryEmail, err)
+			}
+			userErrors.WriteString(fmt.Sprintf("- %s: %v\n", user.PrimaryEmail, err))
+			continue
+		}
+		updated++
+	}
+
+	var resp strings.Builder
+	if input.DryRun {
+		resp.WriteString(fmt.Sprintf("Dry run: the signature of %d of %d user(s) would be replaced\n", rendered, len(users)))
+		if previews.Len() > 0 {
+			resp.WriteString("\nPreview:\n")
+			resp.WriteString(previews.String())
+		}
+	} else {
+		resp.WriteString(fmt.Sprintf("Signature updated for %d of %d user(s)\n", updated, len(users)))
+	}
+	if userErrors.Len() > 0 {
+		if input.DryRun {
+			resp.WriteString("\nUsers whose signature could not be rendered:\n")
+		} else {
+			resp.WriteString("\nUsers that could not be updated:\n")
+		}
+		resp.WriteString(userErrors.String())
+	}
+
+	return nil, ApplyGmailSignatureTemplateOutput{Result: resp.String()}, nil
+}
+
+// registerGmailSendAsTools registers the tools that change send-as addresses
+// and signatures.
+func registerGmailSendAsTools(server *mcp.Server) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "create_gmail_send_as",
+		Description: "Create a Gmail send-as alias with display name, reply-to and signature; external addresses must confirm a verification email before use",
+	}, CreateGmailSendAs)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "verify_gmail_send_as",
+		Description: "Send the verification email for a pending Gmail send-as alias again",
+		Annotations: &mcp.ToolAnnotations{IdempotentHint: true},
+	}, VerifyGmailSendAs)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "set_gmail_signature",
+		Description: "Set or remove the HTML signature of a Gmail send-as address",
+		Annotations: &mcp.ToolAnnotations{IdempotentHint: true},
+	}, SetGmailSignature)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "apply_gmail_signature_template",
+		Description: "Render an HTML signature template from each user's Directory name, title, department and phone and set it as their Gmail signature, for one user or every user in a domain; use dryRun to preview",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: boolPtr(true), IdempotentHint: true},
+	}, ApplyGmailSignatureTemplate)
+}
@@ -0,0 +1,1306 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	admin "google.golang.org/api/admin/directory/v1"
+	"google.golang.org/api/gmail/v1"
+)
+
+// ============================================================================
+// Gmail Input Struct Tests
+// ============================================================================
+
+// gmailInputStructs returns all Gmail Input structs that need to be validated
+func gmailInputStructs() []any {
+	return []any{
+		ListGmailInput{},
+		SearchGmailInput{},
+		GetGmailMessageInput{},
+		SendGmailInput{},
+		GmailAttachmentInput{},
+		CreateGmailDraftInput{},
+		ListGmailDraftsInput{},
+		GetGmailDraftInput{},
+		UpdateGmailDraftInput{},
+		DeleteGmailDraftInput{},
+		SendGmailDraftInput{},
+		ReplyGmailInput{},
+		ForwardGmailInput{},
+		ListGmailLabelsInput{},
+		CreateGmailLabelInput{},
+		UpdateGmailLabelInput{},
+		DeleteGmailLabelInput{},
+		ModifyGmailMessagesInput{},
+		GetGmailThreadInput{},
+		GetGmailAttachmentInput{},
+		TrashGmailMessagesInput{},
+		UntrashGmailMessagesInput{},
+		DeleteGmailMessagesInput{},
+		CleanupGmailInput{},
+		ListGmailFiltersInput{},
+		CreateGmailFilterInput{},
+		DeleteGmailFilterInput{},
+		GetGmailForwardingInput{},
+		AddGmailForwardingAddressInput{},
+		DeleteGmailForwardingAddressInput{},
+		SetGmailAutoForwardingInput{},
+		AuditGmailForwardingInput{},
+		GetVacationSettingsInput{},
+		SetVacationSettingsInput{},
+		ListGmailSendAsInput{},
+		CreateGmailSendAsInput{},
+		VerifyGmailSendAsInput{},
+		SetGmailSignatureInput{},
+		ApplyGmailSignatureTemplateInput{},
+	}
+}
+
+// TestGmailInputStructTagCompleteness verifies that all Gmail Input structs
+// have proper json and jsonschema tags on all fields
+func TestGmailInputStructTagCompleteness(t *testing.T) {
+	for _, input := range gmailInputStructs() {
+		structType := reflect.TypeOf(input)
+		structName := structType.Name()
+
+		t.Run(structName, func(t *testing.T) {
+			for i := range structType.NumField() {
+				field := structType.Field(i)
+
+				if field.Tag.Get("json") == "" {
+					t.Errorf("Field %s.%s is missing json tag", structName, field.Name)
+				}
+				if field.Tag.Get("jsonschema") == "" {
+					t.Errorf("Field %s.%s is missing jsonschema tag", structName, field.Name)
+				}
+			}
+		})
+	}
+}
+
+// TestGmailRequiredFieldsHaveRequiredTag verifies that required Input fields
+// are not marked omitempty in their json tag
+func TestGmailRequiredFieldsHaveRequiredTag(t *testing.T) {
+	requiredFields := map[string][]string{
+		"ListGmailInput":                    {"Email"},
+		"SearchGmailInput":                  {"Email"},
+		"GetGmailMessageInput":              {"Email", "MessageID"},
+		"SendGmailInput":                    {"Email", "To", "Subject"},
+		"CreateGmailDraftInput":             {"Email"},
+		"ListGmailDraftsInput":              {"Email"},
+		"GetGmailDraftInput":                {"Email", "DraftID"},
+		"UpdateGmailDraftInput":             {"Email", "DraftID"},
+		"DeleteGmailDraftInput":             {"Email", "DraftID", "Confirm"},
+		"SendGmailDraftInput":               {"Email", "DraftID"},
+		"ReplyGmailInput":                   {"Email", "MessageID"},
+		"ForwardGmailInput":                 {"Email", "MessageID", "To"},
+		"ListGmailLabelsInput":              {"Email"},
+		"CreateGmailLabelInput":             {"Email", "Name"},
+		"UpdateGmailLabelInput":             {"Email", "Label"},
+		"DeleteGmailLabelInput":             {"Email", "Label", "Confirm"},
+		"ModifyGmailMessagesInput":          {"Email"},
+		"GetGmailThreadInput":               {"Email", "ThreadID"},
+		"TrashGmailMessagesInput":           {"Email"},
+		"UntrashGmailMessagesInput":         {"Email"},
+		"DeleteGmailMessagesInput":          {"Email", "Confirm"},
+		"CleanupGmailInput":                 {"Email", "Query", "Action", "DryRun"},
+		"ListGmailFiltersInput":             {"Email"},
+		"CreateGmailFilterInput":            {"Email"},
+		"DeleteGmailFilterInput":            {"Email", "FilterID", "Confirm"},
+		"GetGmailForwardingInput":           {"Email"},
+		"AddGmailForwardingAddressInput":    {"Email", "ForwardingEmail"},
+		"DeleteGmailForwardingAddressInput": {"Email", "ForwardingEmail", "Confirm"},
+		"SetGmailAutoForwardingInput":       {"Email", "Enabled"},
+		"GetVacationSettingsInput":          {"Email"},
+		"SetVacationSettingsInput":          {"Email", "Enabled"},
+		"ListGmailSendAsInput":              {"Email"},
+		"CreateGmailSendAsInput":            {"Email", "SendAsEmail"},
+		"VerifyGmailSendAsInput":            {"Email", "SendAsEmail"},
+		"SetGmailSignatureInput":            {"Email", "Signature"},
+		"ApplyGmailSignatureTemplateInput":  {"Template", "DryRun"},
+		"GetGmailAttachmentInput":           {"Email", "MessageID"},
+	}
+
+	for _, input := range gmailInputStructs() {
+		structType := reflect.TypeOf(input)
+		structName := structType.Name()
+
+		t.Run(structName, func(t *testing.T) {
+			expectedRequired, ok := requiredFields[structName]
+			if !ok {
+				t.Skipf("No required fields defined for %s", structName)
+				return
+			}
+
+			for _, fieldName := range expectedRequired {
+				field, found := structType.FieldByName(fieldName)
+				if !found {
+					t.Errorf("Expected required field %s not found in %s", fieldName, structName)
+					continue
+				}
+
+				jsonTag := field.Tag.Get("json")
+				if strings.Contains(jsonTag, "omitempty") || strings.Contains(jsonTag, "omitzero") {
+					t.Errorf("Required field %s.%s should not be omitempty in json tag", structName, fieldName)
+				}
+			}
+		})
+	}
+}
+
+// ============================================================================
+// Gmail Message Summary Tests
+// ============================================================================
+
+// TestFormatGmailSummary verifies headers, labels and snippet are rendered
+func TestFormatGmailSummary(t *testing.T) {
+	msg := &gmail.Message{
+		Id:       "m1",
+		ThreadId: "t1",
+		LabelIds: []string{"INBOX", "Label_7"},
+		Snippet:  "See attached",
+		Payload: &gmail.MessagePart{Headers: []*gmail.MessagePartHeader{
+			{Name: "from", Value: "Ann <ann@example.com>"},
+			{Name: "To", Value: "bob@example.com"},
+			{Name: "Date", Value: "Mon, 3 Mar 2025 10:00:00 +0000"},
+			{Name: "Subject", Value: "Q1 report"},
+		}},
+	}
+
+	got := formatGmailSummary(msg, map[string]string{"INBOX": "INBOX", "Label_7": "Finance"})
+	for _, want := range []string{
+		"ID: m1", "Thread ID: t1", "From: Ann <ann@example.com>", "To: bob@example.com",
+		"Subject: Q1 report", "Labels: INBOX, Finance", "Snippet: See attached",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("formatGmailSummary() missing %q in:\n%s", want, got)
+		}
+	}
+
+	// Unknown label IDs fall back to the ID and a missing payload is tolerated.
+	got = formatGmailSummary(&gmail.Message{Id: "m2", LabelIds: []string{"Label_9"}}, nil)
+	if !strings.Contains(got, "Labels: Label_9") {
+		t.Errorf("formatGmailSummary() should fall back to label IDs:\n%s", got)
+	}
+}
+
+// TestFetchGmailMessages verifies results keep their order, failures are
+// reported per message and the concurrency limit is respected
+func TestFetchGmailMessages(t *testing.T) {
+	ids := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+	var running, peak atomic.Int32
+
+	results := fetchGmailMessages(context.Background(), ids, 3, func(ctx context.Context, id string) (*gmail.Message, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		if id == "c" {
+			return nil, errors.New("quota exceeded")
+		}
+		return &gmail.Message{Id: id}, nil
+	})
+
+	if len(results) != len(ids) {
+		t.Fatalf("got %d results, want %d", len(results), len(ids))
+	}
+	for i, r := range results {
+		if r.ID != ids[i] {
+			t.Errorf("results[%d].ID = %q, want %q", i, r.ID, ids[i])
+		}
+		if r.ID == "c" {
+			if r.Err == nil {
+				t.Error("failure for c should be reported")
+			}
+			continue
+		}
+		if r.Err != nil || r.Message.Id != r.ID {
+			t.Errorf("results[%d] = %+v, want message %s", i, r, r.ID)
+		}
+	}
+	if p := peak.Load(); p > 3 {
+		t.Errorf("peak concurrency = %d, want at most 3", p)
+	}
+
+	var b strings.Builder
+	writeGmailFetchFailures(&b, results)
+	if !strings.Contains(b.String(), "- c: quota exceeded") {
+		t.Errorf("writeGmailFetchFailures() = %q", b.String())
+	}
+}
+
+// ============================================================================
+// Gmail MIME Parsing Tests
+// ============================================================================
+
+// loadGmailFixture parses an RFC 822 message from testdata/gmail into the
+// MessagePart tree the Gmail API returns for format=full: transfer encodings
+// are removed, body data is base64url encoded, and parts with a file name
+// carry an attachment ID instead of inline data.
+func loadGmailFixture(t *testing.T, name string) *gmail.MessagePart {
+	t.Helper()
+	raw, err := os.ReadFile(filepath.Join("testdata", "gmail", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rawGmailMessagePart(t, raw)
+}
+
+// rawGmailMessagePart converts an RFC 822 message into a gmail.MessagePart.
+func rawGmailMessagePart(t *testing.T, raw []byte) *gmail.MessagePart {
+	t.Helper()
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("failed to parse message: %v", err)
+	}
+	body, err := io.ReadAll(msg.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buildGmailFixturePart(t, textproto.MIMEHeader(msg.Header), body, "")
+}
+
+// buildGmailFixturePart converts one MIME entity into a gmail.MessagePart.
+func buildGmailFixturePart(t *testing.T, header textproto.MIMEHeader, body []byte, partID string) *gmail.MessagePart {
+	t.Helper()
+	decoder := new(mime.WordDecoder)
+	part := &gmail.MessagePart{PartId: partID, MimeType: "text/plain"}
+	for name, values := range header {
+		for _, v := range values {
+			if decoded, err := decoder.DecodeHeader(v); err == nil {
+				v = decoded
+			}
+			part.Headers = append(part.Headers, &gmail.MessagePartHeader{Name: name, Value: v})
+		}
+	}
+
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err == nil {
+		part.MimeType = mediaType
+	}
+	_, dispParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	part.Filename = dispParams["filename"]
+	if part.Filename == "" {
+		part.Filename = params["name"]
+	}
+
+	if strings.HasPrefix(part.MimeType, "multipart/") {
+		reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+		for i := 0; ; i++ {
+			p, err := reader.NextRawPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("failed to read part: %v", err)
+			}
+			childBody, err := io.ReadAll(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			childID := fmt.Sprint(i)
+			if partID != "" {
+				childID = partID + "." + childID
+			}
+			part.Parts = append(part.Parts, buildGmailFixturePart(t, p.Header, childBody, childID))
+		}
+		part.Body = &gmail.MessagePartBody{}
+		return part
+	}
+
+	switch strings.ToLower(header.Get("Content-Transfer-Encoding")) {
+	case "base64":
+		body, err = base64.StdEncoding.DecodeString(strings.Join(strings.Fields(string(body)), ""))
+	case "quoted-printable":
+		body, err = io.ReadAll(quotedprintable.NewReader(bytes.NewReader(body)))
+	}
+	if err != nil {
+		t.Fatalf("failed to decode part %s: %v", partID, err)
+	}
+
+	part.Body = &gmail.MessagePartBody{Size: int64(len(body))}
+	if part.Filename != "" {
+		part.Body.AttachmentId = "att-" + partID
+	} else {
+		part.Body.Data = base64.URLEncoding.EncodeToString(body)
+	}
+	return part
+}
+
+// TestParseGmailMessageFixtures verifies bodies, headers and attachments are
+// extracted from fixture messages
+func TestParseGmailMessageFixtures(t *testing.T) {
+	testCases := []struct {
+		fixture     string
+		subject     string
+		body        []string
+		notInBody   []string
+		attachments []gmailAttachment
+	}{
+		{
+			fixture: "plain.eml",
+			subject: "Café meeting",
+			body:    []string{"Hi Bob,", "Shall we meet at 10?"},
+		},
+		{
+			fixture:   "alternative.eml",
+			subject:   "Quarterly numbers",
+			body:      []string{"Revenue is up 12% — details in the deck."},
+			notInBody: []string{"<p>", "<b>"},
+		},
+		{
+			fixture: "html_only.eml",
+			subject: "Weekly digest",
+			body: []string{
+				"Weekly digest\nTop stories & updates:\n- New office (https://example.com/office) opens\n- Parking changes\nSee https://example.com\nThanks",
+			},
+			notInBody: []string{"alert", "color: red", "<", "Digest\n"},
+		},
+		{
+			fixture: "latin1.eml",
+			subject: "Réunion",
+			body:    []string{"Voilà le résumé de la réunion."},
+		},
+		{
+			fixture: "mixed_attachments.eml",
+			subject: "Contract and logo",
+			body:    []string{"Please review the attached contract."},
+			attachments: []gmailAttachment{
+				{PartID: "0.1", Filename: "logo.png", MimeType: "image/png", Size: 70, AttachmentID: "att-0.1", ContentID: "logo@example.com", Inline: true},
+				{PartID: "1", Filename: "contract.pdf", MimeType: "application/pdf", Size: 54, AttachmentID: "att-1"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.fixture, func(t *testing.T) {
+			parsed, err := parseGmailMessage(loadGmailFixture(t, tc.fixture))
+			if err != nil {
+				t.Fatalf("parseGmailMessage() error = %v", err)
+			}
+			if got := gmailHeader(parsed.Headers, "Subject"); got != tc.subject {
+				t.Errorf("Subject = %q, want %q", got, tc.subject)
+			}
+			body := parsed.Body()
+			for _, want := range tc.body {
+				if !strings.Contains(body, want) {
+					t.Errorf("Body() missing %q in:\n%s", want, body)
+				}
+			}
+			for _, unwanted := range tc.notInBody {
+				if strings.Contains(body, unwanted) {
+					t.Errorf("Body() should not contain %q:\n%s", unwanted, body)
+				}
+			}
+			if !reflect.DeepEqual(parsed.Attachments, tc.attachments) {
+				t.Errorf("Attachments = %+v\nwant %+v", parsed.Attachments, tc.attachments)
+			}
+		})
+	}
+}
+
+// TestDecodeGmailData verifies padded and unpadded base64url data is accepted
+func TestDecodeGmailData(t *testing.T) {
+	for _, data := range []string{"aGk_Pz4-", "aGk_Pz4-Pw==", "aGk_Pz4-Pw"} {
+		if _, err := decodeGmailData(data); err != nil {
+			t.Errorf("decodeGmailData(%q) error = %v", data, err)
+		}
+	}
+	if _, err := decodeGmailData("not base64!"); err == nil {
+		t.Error("decodeGmailData() should reject invalid data")
+	}
+}
+
+// TestDecodeCharset verifies legacy charsets are converted to UTF-8
+func TestDecodeCharset(t *testing.T) {
+	testCases := []struct {
+		data    []byte
+		charset string
+		want    string
+	}{
+		{[]byte("caf\xe9"), "iso-8859-1", "café"},
+		{[]byte("\x93quoted\x94"), "windows-1252", "\u201cquoted\u201d"},
+		{[]byte("\x82\xa0"), "Shift_JIS", "あ"},
+		{[]byte("plain"), "", "plain"},
+		{[]byte("bad\xff"), "utf-8", "bad\uFFFD"},
+		{[]byte("unknown"), "x-made-up", "unknown"},
+	}
+	for _, tc := range testCases {
+		if got := decodeCharset(tc.data, tc.charset); got != tc.want {
+			t.Errorf("decodeCharset(%q, %q) = %q, want %q", tc.data, tc.charset, got, tc.want)
+		}
+	}
+}
+
+// ============================================================================
+// Gmail Compose Tests
+// ============================================================================
+
+// TestGmailCompositionBuild verifies composed messages parse back into the
+// expected headers, bodies and attachments
+func TestGmailCompositionBuild(t *testing.T) {
+	c := &gmailComposition{
+		From:    "sender@example.com",
+		To:      []string{"Zoë Müller <zoe@example.com>", "bob@example.com, carol@example.com"},
+		Cc:      []string{"dave@example.com"},
+		Bcc:     []string{"erin@example.com"},
+		Subject: "Überweisung für März ✓",
+		HTML:    "<p>Hello <b>Zoë</b></p><p>See <a href=\"https://example.com\">the report</a>.</p>",
+		Date:    time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC),
+		Attachments: []gmailOutgoingAttachment{
+			{Filename: "report.csv", MimeType: "text/csv", Data: []byte("a,b\n1,2\n")},
+			{Filename: "bilan été.pdf", MimeType: "application/pdf", Data: bytes.Repeat([]byte{0, 1, 2, 255}, 100)},
+		},
+	}
+	raw, err := c.build()
+	if err != nil {
+		t.Fatalf("build() error = %v", err)
+	}
+
+	headerBlock := string(raw[:bytes.Index(raw, []byte("\r\n\r\n"))])
+	for _, line := range strings.Split(headerBlock, "\r\n") {
+		for _, r := range line {
+			if r > 127 {
+				t.Fatalf("header line %q contains non-ASCII characters", line)
+			}
+		}
+	}
+
+	payload := rawGmailMessagePart(t, raw)
+	msg, err := parseGmailMessage(payload)
+	if err != nil {
+		t.Fatalf("parseGmailMessage() error = %v", err)
+	}
+	wantHeaders := map[string]string{
+		"From":    "<sender@example.com>",
+		"To":      "Zoë Müller <zoe@example.com>, <bob@example.com>, <carol@example.com>",
+		"Cc":      "<dave@example.com>",
+		"Bcc":     "<erin@example.com>",
+		"Subject": "Überweisung für März ✓",
+		"Date":    "Fri, 01 Mar 2024 09:30:00 +0000",
+	}
+	for name, want := range wantHeaders {
+		if got := gmailHeader(payload.Headers, name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+
+	if payload.MimeType != "multipart/mixed" || len(payload.Parts) != 3 || payload.Parts[0].MimeType != "multipart/alternative" {
+		t.Fatalf("unexpected structure: %s with %d parts", payload.MimeType, len(payload.Parts))
+	}
+	if msg.HTML != c.HTML {
+		t.Errorf("HTML = %q, want %q", msg.HTML, c.HTML)
+	}
+	if got, want := strings.ReplaceAll(msg.Text, "\r\n", "\n"), "Hello Zoë\nSee the report (https://example.com)."; got != want {
+		t.Errorf("Text = %q, want %q", got, want)
+	}
+
+	if len(msg.Attachments) != 2 {
+		t.Fatalf("got %d attachments, want 2", len(msg.Attachments))
+	}
+	for i, a := range msg.Attachments {
+		want := c.Attachments[i]
+		if a.Filename != want.Filename || a.MimeType != want.MimeType || a.Size != int64(len(want.Data)) {
+			t.Errorf("attachment %d = %+v, want %s (%s, %d bytes)", i, a, want.Filename, want.MimeType, len(want.Data))
+		}
+	}
+}
+
+// TestGmailCompositionBuildPlainText verifies a text-only message is a single
+// quoted-printable part
+func TestGmailCompositionBuildPlainText(t *testing.T) {
+	c := &gmailComposition{To: []string{"bob@example.com"}, Subject: "Plain", Text: "line one\nline two with a very long tail " + strings.Repeat("x", 100)}
+	raw, err := c.build()
+	if err != nil {
+		t.Fatalf("build() error = %v", err)
+	}
+	if !bytes.Contains(raw, []byte("Content-Transfer-Encoding: quoted-printable\r\n")) {
+		t.Error("text body should be quoted-printable")
+	}
+	payload := rawGmailMessagePart(t, raw)
+	if payload.MimeType != "text/plain" || len(payload.Parts) != 0 {
+		t.Errorf("unexpected structure: %s with %d parts", payload.MimeType, len(payload.Parts))
+	}
+	msg, err := parseGmailMessage(payload)
+	if err != nil {
+		t.Fatalf("parseGmailMessage() error = %v", err)
+	}
+	if got := strings.ReplaceAll(msg.Text, "\r\n", "\n"); got != c.Text {
+		t.Errorf("Text = %q, want %q", got, c.Text)
+	}
+}
+
+// TestGmailCompositionBuildRejectsInvalidInput verifies bad addresses, missing
+// recipients and header injection are rejected
+func TestGmailCompositionBuildRejectsInvalidInput(t *testing.T) {
+	testCases := map[string]*gmailComposition{
+		"no recipients":     {Subject: "x"},
+		"invalid address":   {To: []string{"not an address"}},
+		"subject injection": {To: []string{"bob@example.com"}, Subject: "hi\r\nBcc: eve@example.com"},
+		"references break":  {To: []string{"bob@example.com"}, References: "<a@x>\n<b@x>"},
+	}
+	for name, c := range testCases {
+		if _, err := c.build(); err == nil {
+			t.Errorf("%s: build() should fail", name)
+		}
+	}
+}
+
+// TestLoadGmailAttachments verifies sandboxed and inline attachments are read
+// and that each attachment names exactly one source
+func TestLoadGmailAttachments(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("MCP_SANDBOX_DIR", dir)
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := loadGmailAttachments(context.Background(), "user@example.com", []GmailAttachmentInput{
+		{FilePath: "notes.txt"},
+		{Content: base64.StdEncoding.EncodeToString([]byte("%PDF-1.4 test")), Filename: "doc.pdf"},
+		{Content: base64.StdEncoding.EncodeToString([]byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n'}), Filename: "blob", MimeType: "image/x-custom"},
+	})
+	if err != nil {
+		t.Fatalf("loadGmailAttachments() error = %v", err)
+	}
+	want := []struct{ name, mimeType, data string }{
+		{"notes.txt", "text/plain; charset=utf-8", "hello"},
+		{"doc.pdf", "application/pdf", "%PDF-1.4 test"},
+		{"blob", "image/x-custom", "\x89PNG\r\n\x1a\n"},
+	}
+	for i, w := range want {
+		if got[i].Filename != w.name || got[i].MimeType != w.mimeType || string(got[i].Data) != w.data {
+			t.Errorf("attachment %d = %s (%s, %q), want %s (%s, %q)", i, got[i].Filename, got[i].MimeType, got[i].Data, w.name, w.mimeType, w.data)
+		}
+	}
+
+	invalid := map[string]GmailAttachmentInput{
+		"no source":        {},
+		"two sources":      {FilePath: "notes.txt", Content: "aGk="},
+		"missing filename": {Content: "aGk="},
+		"bad base64":       {Content: "not base64!", Filename: "x"},
+		"outside sandbox":  {FilePath: "../escape.txt"},
+	}
+	for name, in := range invalid {
+		if _, err := loadGmailAttachments(context.Background(), "user@example.com", []GmailAttachmentInput{in}); err == nil {
+			t.Errorf("%s: loadGmailAttachments() should fail", name)
+		}
+	}
+}
+
+// TestGmailCompositionSetReply verifies replies join the original thread with
+// In-Reply-To, References, subject and recipient defaults
+func TestGmailCompositionSetReply(t *testing.T) {
+	original := &gmail.Message{
+		Id:       "m2",
+		ThreadId: "t1",
+		Payload: &gmail.MessagePart{Headers: []*gmail.MessagePartHeader{
+			{Name: "From", Value: "Alice <alice@example.com>"},
+			{Name: "Subject", Value: "Quarterly plan"},
+			{Name: "Message-Id", Value: "<m2@example.com>"},
+			{Name: "References", Value: "<m0@example.com>\r\n <m1@example.com>"},
+		}},
+	}
+
+	c := &gmailComposition{Text: "Sounds good"}
+	c.setReply(original)
+	if c.ThreadID != "t1" || c.InReplyTo != "<m2@example.com>" {
+		t.Errorf("ThreadID, InReplyTo = %q, %q", c.ThreadID, c.InReplyTo)
+	}
+	if want := "<m0@example.com> <m1@example.com> <m2@example.com>"; c.References != want {
+		t.Errorf("References = %q, want %q", c.References, want)
+	}
+	if c.Subject != "Re: Quarterly plan" || len(c.To) != 1 || c.To[0] != `"Alice" <alice@example.com>` {
+		t.Errorf("Subject, To = %q, %v", c.Subject, c.To)
+	}
+	if _, err := c.build(); err != nil {
+		t.Errorf("build() error = %v", err)
+	}
+
+	original.Payload.Headers = append(original.Payload.Headers,
+		&gmail.MessagePartHeader{Name: "Reply-To", Value: "list@example.com"})
+	original.Payload.Headers[1].Value = "RE: Quarterly plan"
+	c = &gmailComposition{To: []string{"bob@example.com"}}
+	c.setReply(original)
+	if c.Subject != "RE: Quarterly plan" || c.To[0] != "bob@example.com" {
+		t.Errorf("explicit recipient: Subject, To = %q, %v", c.Subject, c.To)
+	}
+	c = &gmailComposition{}
+	c.setReply(original)
+	if c.To[0] != "<list@example.com>" {
+		t.Errorf("Reply-To should be preferred over From, got %v", c.To)
+	}
+}
+
+// TestGmailDraftWithoutRecipients verifies drafts may be saved before any
+// recipient is known
+func TestGmailDraftWithoutRecipients(t *testing.T) {
+	draft, raw, err := newGmailDraft(&gmailComposition{Subject: "Notes", Text: "todo", ThreadID: "t9"})
+	if err != nil {
+		t.Fatalf("newGmailDraft() error = %v", err)
+	}
+	if draft.Message.ThreadId != "t9" || !bytes.Contains(raw, []byte("Subject: Notes\r\n")) {
+		t.Errorf("unexpected draft %+v\n%s", draft.Message, raw)
+	}
+}
+
+// ============================================================================
+// Gmail Reply and Forward Tests
+// ============================================================================
+
+// TestGmailReplyRecipients verifies reply and reply-all addressing, including
+// replies to messages the user sent and exclusion of the user's own address
+func TestGmailReplyRecipients(t *testing.T) {
+	headers := func(kv ...string) []*gmail.MessagePartHeader {
+		var h []*gmail.MessagePartHeader
+		for i := 0; i < len(kv); i += 2 {
+			h = append(h, &gmail.MessagePartHeader{Name: kv[i], Value: kv[i+1]})
+		}
+		return h
+	}
+
+	testCases := []struct {
+		name     string
+		headers  []*gmail.MessagePartHeader
+		replyAll bool
+		to, cc   []string
+	}{
+		{
+			name:    "reply to sender",
+			headers: headers("From", "Alice <alice@example.com>", "To", "me@example.com, bob@example.com"),
+			to:      []string{`"Alice" <alice@example.com>`},
+		},
+		{
+			name:     "reply all excludes self",
+			headers:  headers("From", "alice@example.com", "To", "Me <ME@example.com>, bob@example.com", "Cc", "carol@example.com, alice@example.com"),
+			replyAll: true,
+			to:       []string{"<alice@example.com>"},
+			cc:       []string{"<bob@example.com>", "<carol@example.com>"},
+		},
+		{
+			name:     "reply-to wins over from",
+			headers:  headers("From", "alice@example.com", "Reply-To", "list@example.com", "To", "me@example.com"),
+			replyAll: true,
+			to:       []string{"<list@example.com>"},
+		},
+		{
+			name:     "own message goes back to its recipients",
+			headers:  headers("From", "me@example.com", "To", "bob@example.com", "Cc", "carol@example.com"),
+			replyAll: true,
+			to:       []string{"<bob@example.com>"},
+			cc:       []string{"<carol@example.com>"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			to, cc := gmailReplyRecipients(tc.headers, "me@example.com", tc.replyAll)
+			if !reflect.DeepEqual(to, tc.to) || !reflect.DeepEqual(cc, tc.cc) {
+				t.Errorf("got to=%v cc=%v, want to=%v cc=%v", to, cc, tc.to, tc.cc)
+			}
+		})
+	}
+}
+
+// TestGmailReplyAndForwardBodies verifies the original is quoted below a
+// reply and reproduced below a forward note
+func TestGmailReplyAndForwardBodies(t *testing.T) {
+	original := &parsedGmailMessage{
+		Headers: []*gmail.MessagePartHeader{
+			{Name: "From", Value: "Alice <alice@example.com>"},
+			{Name: "Date", Value: "Tue, 5 Mar 2024 14:07:00 +0000"},
+			{Name: "Subject", Value: "Plan"},
+			{Name: "To", Value: "me@example.com"},
+		},
+		Text: "Line one\n\n> earlier\nLine <two>\n",
+	}
+
+	text, htmlBody := gmailReplyBody("Thanks!", "", original)
+	wantText := "Thanks!\n\nOn Tue, Mar 5, 2024 at 2:07 PM, Alice <alice@example.com> wrote:\n> Line one\n>\n>> earlier\n> Line <two>\n"
+	if text != wantText || htmlBody != "" {
+		t.Errorf("reply text = %q, html = %q\nwant text %q", text, htmlBody, wantText)
+	}
+
+	text, htmlBody = gmailReplyBody("", "<p>Thanks!</p>", original)
+	if !strings.HasPrefix(text, "Thanks!\n\nOn ") {
+		t.Errorf("reply text should be derived from HTML, got %q", text)
+	}
+	for _, want := range []string{"<p>Thanks!</p><div class=\"gmail_quote\">", "Alice &lt;alice@example.com&gt; wrote:", "<blockquote", "Line &lt;two&gt;"} {
+		if !strings.Contains(htmlBody, want) {
+			t.Errorf("reply HTML missing %q:\n%s", want, htmlBody)
+		}
+	}
+
+	text, _ = gmailForwardBody("FYI", "", original)
+	wantText = "FYI\n\n---------- Forwarded message ---------\nFrom: Alice <alice@example.com>\nDate: Tue, 5 Mar 2024 14:07:00 +0000\nSubject: Plan\nTo: me@example.com\n\nLine one\n\n> earlier\nLine <two>\n\n"
+	if text != wantText {
+		t.Errorf("forward text = %q\nwant %q", text, wantText)
+	}
+
+	for subject, want := range map[string]string{"Plan": "Fwd: Plan", "FW: Plan": "FW: Plan", "fwd: Plan": "fwd: Plan"} {
+		if got := gmailForwardSubject(subject); got != want {
+			t.Errorf("gmailForwardSubject(%q) = %q, want %q", subject, got, want)
+		}
+	}
+}
+
+// TestAttachmentEntityInline verifies parts with a Content-ID are sent inline
+func TestAttachmentEntityInline(t *testing.T) {
+	e := attachmentEntity(gmailOutgoingAttachment{Filename: "logo.png", MimeType: "image/png", ContentID: "logo@example.com", Data: []byte("png")})
+	if got := e.header.Get("Content-ID"); got != "<logo@example.com>" {
+		t.Errorf("Content-ID = %q", got)
+	}
+	if got := e.header.Get("Content-Disposition"); !strings.HasPrefix(got, "inline;") {
+		t.Errorf("Content-Disposition = %q, want inline", got)
+	}
+	e = attachmentEntity(gmailOutgoingAttachment{Filename: "a.txt", MimeType: "text/plain", ContentID: "bad>\r\nX-Evil: 1", Data: []byte("x")})
+	if e.header.Get("Content-ID") != "" || !strings.HasPrefix(e.header.Get("Content-Disposition"), "attachment;") {
+		t.Errorf("invalid Content-ID should be dropped, got %v", e.header)
+	}
+}
+
+// ============================================================================
+// Gmail Label Tests
+// ============================================================================
+
+// testGmailLabels is a small mailbox label set with nested user labels.
+func testGmailLabels() []*gmail.Label {
+	return []*gmail.Label{
+		{Id: "INBOX", Name: "INBOX", Type: "system"},
+		{Id: "UNREAD", Name: "UNREAD", Type: "system"},
+		{Id: "Label_1", Name: "Clients", Type: "user", Color: &gmail.LabelColor{BackgroundColor: "#fb4c2f", TextColor: "#ffffff"}},
+		{Id: "Label_2", Name: "Clients/Acme", Type: "user", MessagesTotal: 12, MessagesUnread: 3},
+		{Id: "Label_3", Name: "Clients/Acme/Invoices", Type: "user"},
+		{Id: "Label_4", Name: "Archive/2023", Type: "user", LabelListVisibility: "labelHide"},
+		{Id: "Label_5", Name: "clientsx", Type: "user"},
+	}
+}
+
+// TestGmailLabelChanges verifies actions and label names resolve to label IDs
+func TestGmailLabelChanges(t *testing.T) {
+	idx := newGmailLabelIndex(testGmailLabels())
+
+	add, remove, err := idx.labelChanges([]string{"archive", "READ", "star"}, []string{"clients/acme", "Label_1"}, nil)
+	if err != nil {
+		t.Fatalf("labelChanges() error = %v", err)
+	}
+	if want := []string{"STARRED", "Label_2", "Label_1"}; !reflect.DeepEqual(add, want) {
+		t.Errorf("add = %v, want %v", add, want)
+	}
+	if want := []string{"INBOX", "UNREAD"}; !reflect.DeepEqual(remove, want) {
+		t.Errorf("remove = %v, want %v", remove, want)
+	}
+
+	invalid := []struct {
+		actions, add, remove []string
+	}{
+		{},
+		{actions: []string{"snooze"}},
+		{add: []string{"Missing"}},
+		{actions: []string{"unread"}, remove: []string{"UNREAD"}},
+	}
+	for _, tc := range invalid {
+		if _, _, err := idx.labelChanges(tc.actions, tc.add, tc.remove); err == nil {
+			t.Errorf("labelChanges(%v, %v, %v) should fail", tc.actions, tc.add, tc.remove)
+		}
+	}
+
+	children := idx.children("clients")
+	if len(children) != 2 || children[0].Id != "Label_2" || children[1].Id != "Label_3" {
+		t.Errorf("children(clients) = %v", children)
+	}
+}
+
+// TestFormatGmailLabels verifies user labels are rendered as a tree under
+// their existing parents
+func TestFormatGmailLabels(t *testing.T) {
+	got := formatGmailLabels(testGmailLabels())
+	want := `System labels (2):
+- INBOX (ID: INBOX)
+- UNREAD (ID: UNREAD)
+
+User labels (5):
+- Archive/2023 (ID: Label_4, hidden)
+- Clients (ID: Label_1, color: #ffffff on #fb4c2f)
+  - Acme (ID: Label_2, 12 messages, 3 unread)
+    - Invoices (ID: Label_3)
+- clientsx (ID: Label_5)
+`
+	if got != want {
+		t.Errorf("formatGmailLabels() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+// TestNewGmailLabelColor verifies label colors are validated and normalized
+func TestNewGmailLabelColor(t *testing.T) {
+	color, err := newGmailLabelColor("#FB4C2F", "#ffffff")
+	if err != nil || color.BackgroundColor != "#fb4c2f" || color.TextColor != "#ffffff" {
+		t.Errorf("newGmailLabelColor() = %+v, %v", color, err)
+	}
+	if color, err := newGmailLabelColor("", ""); color != nil || err != nil {
+		t.Errorf("no colors should give nil, got %+v, %v", color, err)
+	}
+	for _, pair := range [][2]string{{"#fb4c2f", ""}, {"red", "#ffffff"}, {"#fff", "#000"}} {
+		if _, err := newGmailLabelColor(pair[0], pair[1]); err == nil {
+			t.Errorf("newGmailLabelColor(%q, %q) should fail", pair[0], pair[1])
+		}
+	}
+}
+
+// ============================================================================
+// Gmail Thread Tests
+// ============================================================================
+
+// TestStripGmailQuotes verifies quoted replies, attribution lines and
+// signatures are removed while forwarded content is kept
+func TestStripGmailQuotes(t *testing.T) {
+	testCases := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "gmail attribution",
+			body: "Sounds good.\r\n\r\nOn Tue, Mar 5, 2024 at 2:07 PM Alice <alice@example.com> wrote:\r\n> Shall we meet?\r\n",
+			want: "Sounds good.",
+		},
+		{
+			name: "wrapped attribution",
+			body: "Yes.\n\nOn Tue, Mar 5, 2024 at 2:07 PM Alice Example <\nalice@example.com> wrote:\n\n> Shall we meet?\n",
+			want: "Yes.",
+		},
+		{
+			name: "inline quotes and signature",
+			body: "> Can you send the deck?\nAttached.\n\n> And the numbers?\nBelow.\n\n-- \nBob Example\nSales",
+			want: "Attached.\n\nBelow.",
+		},
+		{
+			name: "outlook header block",
+			body: "Approved.\n\n________________________________\nFrom: Carol <carol@example.com>\nSent: Monday, March 4, 2024 9:00 AM\nTo: Bob\nSubject: Budget\n\nPlease approve.",
+			want: "Approved.\n\n________________________________",
+		},
+		{
+			name: "original message marker",
+			body: "Done.\n-----Original Message-----\nFrom: Carol\nPlease do it.",
+			want: "Done.",
+		},
+		{
+			name: "mobile footer",
+			body: "On my way\n\nSent from my iPhone\n",
+			want: "On my way",
+		},
+		{
+			name: "forward is kept",
+			body: "FYI\n\n---------- Forwarded message ---------\nFrom: Carol <carol@example.com>\nDate: Mon, Mar 4, 2024\nSubject: Budget\n\nOn second thought the budget is fine.\n> old line",
+			want: "FYI\n\n---------- Forwarded message ---------\nFrom: Carol <carol@example.com>\nDate: Mon, Mar 4, 2024\nSubject: Budget\n\nOn second thought the budget is fine.\n> old line",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := stripGmailQuotes(tc.body); got != tc.want {
+				t.Errorf("stripGmailQuotes() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+// TestGmailThreadBodyHTML verifies quoted blocks and signatures are removed
+// from HTML-only messages but Gmail forwards are kept
+func TestGmailThreadBodyHTML(t *testing.T) {
+	reply := &parsedGmailMessage{HTML: `<div dir="ltr">Thanks, <b>done</b>.<br><div class="gmail_signature">Bob | Sales</div></div>` +
+		`<br><div class="gmail_quote"><div class="gmail_attr">On Tue, Alice wrote:</div><blockquote class="gmail_quote">Please do it.</blockquote></div>`}
+	if got := gmailThreadBody(reply); got != "Thanks, done." {
+		t.Errorf("reply body = %q", got)
+	}
+
+	forward := &parsedGmailMessage{HTML: `<div>See below</div><div class="gmail_quote"><div class="gmail_attr">---------- Forwarded message ---------<br>From: Carol</div><div>Budget is fine.</div></div>`}
+	got := gmailThreadBody(forward)
+	for _, want := range []string{"See below", "Forwarded message", "Budget is fine."} {
+		if !strings.Contains(got, want) {
+			t.Errorf("forward body missing %q: %q", want, got)
+		}
+	}
+}
+
+// TestGmailThreadParticipants verifies participants are listed once in order
+// of appearance with the number of messages each sent
+func TestGmailThreadParticipants(t *testing.T) {
+	msg := func(from, to, cc string) *parsedGmailMessage {
+		return &parsedGmailMessage{Headers: []*gmail.MessagePartHeader{
+			{Name: "From", Value: from}, {Name: "To", Value: to}, {Name: "Cc", Value: cc},
+		}}
+	}
+	got := gmailThreadParticipants([]*parsedGmailMessage{
+		msg("alice@example.com", "Bob <bob@example.com>", "carol@example.com"),
+		msg("Bob <BOB@example.com>", "Alice Example <alice@example.com>", ""),
+		msg("alice@example.com", "bob@example.com, dave@example.com", "not an address <"),
+	})
+	want := []gmailParticipant{
+		{Address: "alice@example.com", Name: "Alice Example", Sent: 2},
+		{Address: "bob@example.com", Name: "Bob", Sent: 1},
+		{Address: "carol@example.com"},
+		{Address: "dave@example.com"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("gmailThreadParticipants() = %+v\nwant %+v", got, want)
+	}
+}
+
+// ============================================================================
+// Gmail Attachment Tests
+// ============================================================================
+
+// TestSelectGmailAttachments verifies attachments are found by attachment ID,
+// part ID or file name
+func TestSelectGmailAttachments(t *testing.T) {
+	attachments := []gmailAttachment{
+		{PartID: "1", Filename: "invoice.pdf", AttachmentID: "ANGjdJ1"},
+		{PartID: "2", Filename: "Invoice.PDF", AttachmentID: "ANGjdJ2"},
+		{PartID: "3", Filename: "logo.png", AttachmentID: "ANGjdJ3"},
+	}
+
+	testCases := []struct {
+		key  string
+		want []string
+	}{
+		{"", []string{"1", "2", "3"}},
+		{"ANGjdJ3", []string{"3"}},
+		{"2", []string{"2"}},
+		{"invoice.pdf", []string{"1", "2"}},
+	}
+	for _, tc := range testCases {
+		got, err := selectGmailAttachments(attachments, tc.key)
+		if err != nil {
+			t.Errorf("selectGmailAttachments(%q) error = %v", tc.key, err)
+			continue
+		}
+		var parts []string
+		for _, a := range got {
+			parts = append(parts, a.PartID)
+		}
+		if !reflect.DeepEqual(parts, tc.want) {
+			t.Errorf("selectGmailAttachments(%q) = %v, want %v", tc.key, parts, tc.want)
+		}
+	}
+
+	if _, err := selectGmailAttachments(attachments, "missing.doc"); err == nil {
+		t.Error("unknown attachment should fail")
+	}
+	if _, err := selectGmailAttachments(nil, ""); err == nil {
+		t.Error("message without attachments should fail")
+	}
+}
+
+// TestGmailAttachmentSaveLocalKeepsSameNamedFiles verifies attachments that
+// share a file name are all saved, with or without overwrite
+func TestGmailAttachmentSaveLocalKeepsSameNamedFiles(t *testing.T) {
+	for _, overwrite := range []bool{false, true} {
+		dir := t.TempDir()
+		t.Setenv("MCP_SANDBOX_DIR", dir)
+
+		f := &gmailAttachmentFiler{ctx: context.Background(), messageID: "m1", overwrite: overwrite}
+		attachments := []gmailAttachment{
+			{PartID: "1.1", Filename: "image001.png", Data: base64.URLEncoding.EncodeToString([]byte("first"))},
+			{PartID: "1.2", Filename: "image001.png", Data: base64.URLEncoding.EncodeToString([]byte("second"))},
+		}
+		for _, a := range attachments {
+			if err := f.saveLocal(a, dir); err != nil {
+				t.Fatalf("overwrite=%t: saveLocal() error = %v", overwrite, err)
+			}
+		}
+
+		for name, want := range map[string]string{"image001.png": "first", "image001 (1.2).png": "second"} {
+			got, err := os.ReadFile(filepath.Join(dir, name))
+			if err != nil || string(got) != want {
+				t.Errorf("overwrite=%t: %s = %q, %v; want %q", overwrite, name, got, err, want)
+			}
+		}
+		if strings.Contains(f.report.String(), "Skipped") {
+			t.Errorf("overwrite=%t: report = %q", overwrite, f.report.String())
+		}
+	}
+}
+
+// TestFormatGmailAttachmentContent verifies text is returned directly, HTML is
+// converted and binary content is base64 encoded
+func TestFormatGmailAttachmentContent(t *testing.T) {
+	testCases := []struct {
+		mimeType string
+		data     []byte
+		want     string
+	}{
+		{"text/csv", []byte("a,b\n1,2\n"), "Content:\na,b\n1,2\n"},
+		{"text/html; charset=utf-8", []byte("<p>Total: <b>42</b></p>"), "Content (converted from HTML):\nTotal: 42"},
+		{"application/pdf", []byte("%PDF\x00\x01"), "Content (base64):\nJVBERgAB"},
+		{"text/plain", []byte("bad\xff"), "Content (base64):\nYmFk/w=="},
+	}
+	for _, tc := range testCases {
+		got, err := formatGmailAttachmentContent(gmailAttachment{PartID: "1", Filename: "f", MimeType: tc.mimeType}, tc.data)
+		if err != nil {
+			t.Errorf("%s: error = %v", tc.mimeType, err)
+			continue
+		}
+		if !strings.HasSuffix(got, tc.want) {
+			t.Errorf("%s: got %q, want suffix %q", tc.mimeType, got, tc.want)
+		}
+	}
+
+	if _, err := formatGmailAttachmentContent(gmailAttachment{MimeType: "text/plain"}, make([]byte, maxInlineContentSize+1)); err == nil {
+		t.Error("oversized attachment should fail")
+	}
+	if got := gmailAttachmentName(gmailAttachment{PartID: "0.2", Filename: "../x/y.pdf"}); got != ".._x_y.pdf" {
+		t.Errorf("gmailAttachmentName() = %q", got)
+	}
+	if got := gmailAttachmentName(gmailAttachment{PartID: "0.2"}); got != "attachment-0.2" {
+		t.Errorf("gmailAttachmentName() = %q", got)
+	}
+}
+
+// TestGmailCleanupRejectsUnsafeInput verifies that the cleanup and bulk tools
+// refuse unsafe or malformed input before creating a Gmail client.
+func TestGmailCleanupRejectsUnsafeInput(t *testing.T) {
+	t.Setenv("GMAIL_ENABLE_DELETE", "")
+	ctx := context.Background()
+
+	testCases := []struct {
+		name  string
+		input CleanupGmailInput
+		want  string
+	}{
+		{"empty query", CleanupGmailInput{Query: "  ", Action: "trash"}, "query is required"},
+		{"delete without confirm", CleanupGmailInput{Query: "older_than:1y", Action: "delete"}, "requires confirm"},
+		{"delete disabled", CleanupGmailInput{Query: "older_than:1y", Action: "delete", Confirm: true}, "GMAIL_ENABLE_DELETE"},
+		{"unknown action", CleanupGmailInput{Query: "older_than:1y", Action: "archive", DryRun: true}, "invalid action"},
+		{"too many", CleanupGmailInput{Query: "older_than:1y", Action: "trash", MaxMessages: 50001}, "maxMessages"},
+		{"negative max", CleanupGmailInput{Query: "older_than:1y", Action: "trash", MaxMessages: -1}, "cannot be negative"},
+	}
+	for _, tc := range testCases {
+		_, _, err := CleanupGmail(ctx, nil, tc.input)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: error = %v, want %q", tc.name, err, tc.want)
+		}
+	}
+
+	if _, _, err := DeleteGmailMessages(ctx, nil, DeleteGmailMessagesInput{MessageIDs: []string{"m1"}}); err == nil || !strings.Contains(err.Error(), "requires confirm") {
+		t.Errorf("DeleteGmailMessages without confirm: error = %v", err)
+	}
+	if _, _, err := TrashGmailMessages(ctx, nil, TrashGmailMessagesInput{}); err == nil || !strings.Contains(err.Error(), "messageIds or threadIds") {
+		t.Errorf("TrashGmailMessages without IDs: error = %v", err)
+	}
+}
+
+// TestNewGmailFilterAction verifies that filter actions map to label changes
+// and forwarding, and that empty or contradictory actions are rejected.
+func TestNewGmailFilterAction(t *testing.T) {
+	idx := newGmailLabelIndex([]*gmail.Label{
+		{Id: "INBOX", Name: "INBOX", Type: "system"},
+		{Id: "TRASH", Name: "TRASH", Type: "system"},
+		{Id: "SPAM", Name: "SPAM", Type: "system"},
+		{Id: "Label_1", Name: "Receipts"},
+	})
+
+	action, err := newGmailFilterAction(idx, CreateGmailFilterInput{Actions: []string{"archive", "never_spam"}, AddLabels: []string{"receipts"}})
+	if err != nil {
+		t.Fatalf("newGmailFilterAction() error = %v", err)
+	}
+	if got := describeGmailFilterAction(action, idx); got != "add Receipts, remove INBOX, remove SPAM" {
+		t.Errorf("describeGmailFilterAction() = %q", got)
+	}
+
+	action, err = newGmailFilterAction(idx, CreateGmailFilterInput{Forward: "archive@example.com"})
+	if err != nil || action.Forward != "archive@example.com" || len(action.AddLabelIds) > 0 {
+		t.Errorf("forward-only action = %+v, %v", action, err)
+	}
+	if _, err := newGmailFilterAction(idx, CreateGmailFilterInput{}); err == nil {
+		t.Error("filter without actions should fail")
+	}
+	if _, err := newGmailFilterAction(idx, CreateGmailFilterInput{Actions: []string{"trash"}, RemoveLabels: []string{"TRASH"}}); err == nil {
+		t.Error("adding and removing TRASH should fail")
+	}
+}
+
+// TestNewGmailFilterCriteria verifies that filter criteria are built and
+// described as Gmail search syntax, and that invalid criteria are rejected.
+func TestNewGmailFilterCriteria(t *testing.T) {
+	c, err := newGmailFilterCriteria(CreateGmailFilterInput{From: "billing@example.com", HasAttachment: true, Size: 1000000})
+	if err != nil {
+		t.Fatalf("newGmailFilterCriteria() error = %v", err)
+	}
+	if got := describeGmailFilterCriteria(c); got != "from:(billing@example.com) has:attachment larger:1000000" {
+		t.Errorf("describeGmailFilterCriteria() = %q", got)
+	}
+
+	if _, err := newGmailFilterCriteria(CreateGmailFilterInput{ExcludeChats: true}); err == nil {
+		t.Error("criteria without conditions should fail")
+	}
+	if _, err := newGmailFilterCriteria(CreateGmailFilterInput{Size: 10, SizeComparison: "bigger"}); err == nil {
+		t.Error("invalid sizeComparison should fail")
+	}
+}
+
+// TestGmailForwardingFindings verifies that only auto-forwarding and filters
+// sending mail outside the internal domains are reported.
+func TestGmailForwardingFindings(t *testing.T) {
+	internal := map[string]bool{"example.com": true}
+	filters := []*gmail.Filter{
+		{Id: "f1", Criteria: &gmail.FilterCriteria{From: "boss@example.com"}, Action: &gmail.FilterAction{Forward: "me@gmail.com"}},
+		{Id: "f2", Criteria: &gmail.FilterCriteria{Subject: "invoice"}, Action: &gmail.FilterAction{Forward: "ap@Example.com"}},
+		{Id: "f3", Action: &gmail.FilterAction{AddLabelIds: []string{"STARRED"}}},
+	}
+
+	got := gmailForwardingFindings(&gmail.AutoForwarding{Enabled: true, EmailAddress: "me@outside.org", Disposition: "archive"}, filters, internal)
+	want := []string{
+		"auto-forwarding enabled, to me@outside.org (original: archive)",
+		"filter f1 forwards from:(boss@example.com) to me@gmail.com",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("gmailForwardingFindings() = %q, want %q", got, want)
+	}
+
+	if got := gmailForwardingFindings(&gmail.AutoForwarding{Enabled: true, EmailAddress: "team@example.com"}, nil, internal); len(got) != 0 {
+		t.Errorf("internal auto-forwarding flagged: %q", got)
+	}
+}
+
+// TestNewGmailVacationSettings verifies that vacation input is converted to
+// Gmail settings and that missing bodies or bad schedules are rejected.
+func TestNewGmailVacationSettings(t *testing.T) {
+	input := SetVacationSettingsInput{
+		Enabled:          true,
+		StartTime:        "2025-07-01T09:00:00+02:00",
+		EndTime:          "2025-07-15T18:00:00+02:00",
+		Subject:          "Away",
+		Body:             "Back on the 16th.",
+		RestrictToDomain: true,
+	}
+	v, err := newGmailVacationSettings(input)
+	if err != nil {
+		t.Fatalf("newGmailVacationSettings() error = %v", err)
+	}
+	if v.StartTime != 1751353200000 || v.EndTime != 1752595200000 {
+		t.Errorf("times = %d, %d", v.StartTime, v.EndTime)
+	}
+	got := describeGmailVacation(v)
+	for _, want := range []string{"Vacation responder: on", "Start: 2025-07-01T07:00:00Z", "Replies to: the domain only", "Subject: Away", "Back on the 16th."} {
+		if !strings.Contains(got, want) {
+			t.Errorf("describeGmailVacation() missing %q in %q", want, got)
+		}
+	}
+
+	for name, bad := range map[string]SetVacationSettingsInput{
+		"no body":          {Enabled: true},
+		"bad time":         {StartTime: "tomorrow"},
+		"end before start": {StartTime: input.EndTime, EndTime: input.StartTime},
+		"event without end": {
+			Enabled: true, Body: "x", StartTime: input.StartTime, CreateCalendarEvent: true,
+		},
+		"event while disabled": {
+			Body: "x", StartTime: input.StartTime, EndTime: input.EndTime, CreateCalendarEvent: true,
+		},
+	} {
+		if _, err := newGmailVacationSettings(bad); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+// TestNewOutOfOfficeEvent verifies the defaults of the out-of-office event
+// and that an unknown declineMode is rejected.
+func TestNewOutOfOfficeEvent(t *testing.T) {
+	event, err := newOutOfOfficeEvent(SetVacationSettingsInput{
+		StartTime: "2025-07-01T09:00:00+02:00",
+		EndTime:   "2025-07-15T18:00:00+02:00",
+		HTMLBody:  "<p>Back on the <b>16th</b>.</p>",
+	})
+	if err != nil {
+		t.Fatalf("newOutOfOfficeEvent() error = %v", err)
+	}
+	if event.EventType != "outOfOffice" || event.Summary != "Out of office" {
+		t.Errorf("event = %q %q", event.EventType, event.Summary)
+	}
+	if p := event.OutOfOfficeProperties; p.AutoDeclineMode != "declineOnlyNewConflictingInvitations" || p.DeclineMessage != "Back on the 16th." {
+		t.Errorf("OutOfOfficeProperties = %+v", p)
+	}
+
+	if _, err := newOutOfOfficeEvent(SetVacationSettingsInput{DeclineMode: "some"}); err == nil {
+		t.Error("invalid declineMode should fail")
+	}
+}
+
+// TestDirectorySignatureFields verifies that signature fields come from a
+// Directory user's primary organization and work phone, and that templates
+// render them HTML-escaped and reject unknown fields.
+func TestDirectorySignatureFields(t *testing.T) {
+	// Organizations and phones arrive as untyped JSON from the Directory API.
+	var user admin.User
+	err := json.Unmarshal([]byte(`{
+		"primaryEmail": "ada@example.com",
+		"name": {"fullName": "Ada Lovelace", "givenName": "Ada", "familyName": "Lovelace"},
+		"organizations": [
+			{"title": "Intern", "department": "Sales"},
+			{"title": "Chief Analyst", "department": "R&D", "primary": true}
+		],
+		"phones": [
+			{"type": "mobile", "value": "+1 555 0100"},
+			{"type": "work", "value": "+1 555 0199"}
+		]
+	}`), &user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := directorySignatureFields(&user)
+	if err != nil {
+		t.Fatalf("directorySignatureFields() error = %v", err)
+	}
+	want := gmailSignatureFields{
+		Name: "Ada Lovelace", FirstName: "Ada", LastName: "Lovelace", Email: "ada@example.com",
+		Title: "Chief Analyst", Department: "R&D", Phone: "+1 555 0199",
+	}
+	if got != want {
+		t.Errorf("directorySignatureFields() = %+v, want %+v", got, want)
+	}
+
+	tmpl, err := parseGmailSignatureTemplate(`<b>{{.Name}}</b> | {{.Title}}, {{.Department}}{{if .Phone}} | Tel: {{.Phone}}{{end}}`)
+	if err != nil {
+		t.Fatalf("parseGmailSignatureTemplate() error = %v", err)
+	}
+	sig, err := renderGmailSignature(tmpl, got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sig != "<b>Ada Lovelace</b> | Chief Analyst, R&amp;D | Tel: &#43;1 555 0199" {
+		t.Errorf("renderGmailSignature() = %q", sig)
+	}
+	if sig, _ := renderGmailSignature(tmpl, gmailSignatureFields{Name: "Bob"}); sig != "<b>Bob</b> | ," {
+		t.Errorf("renderGmailSignature() without phone = %q", sig)
+	}
+
+	for _, bad := range []string{"{{.Name", "{{.Manager}}"} {
+		if _, err := parseGmailSignatureTemplate(bad); err == nil {
+			t.Errorf("parseGmailSignatureTemplate(%q) should fail", bad)
+		}
+	}
+}
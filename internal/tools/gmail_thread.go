@@ -0,0 +1,297 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.orx.me/mcp/google-workspace/internal/utils"
+	"golang.org/x/net/html"
+)
+
+// GetGmailThreadInput defines input for get_gmail_thread tool
+type GetGmailThreadInput struct {
+	Email         string `json:"email" jsonschema:"Email address to access Gmail"`
+	ThreadID      string `json:"threadId" jsonschema:"Thread ID (from search_gmail or get_gmail_message)"`
+	IncludeQuoted bool   `json:"includeQuoted,omitempty" jsonschema:"Return full message bodies instead of stripping quoted replies and signatures"`
+}
+
+// GetGmailThreadOutput defines output for get_gmail_thread tool
+type GetGmailThreadOutput struct {
+	Thread string `json:"thread" jsonschema:"Participants and every message in order with sender, date and body"`
+}
+
+var (
+	// gmailAttributionLine matches "On <date>, <sender> wrote:".
+	gmailAttributionLine = regexp.MustCompile(`(?i)^on\s.+\swrote:$`)
+	// gmailAttributionEnd matches the second half of an attribution that was
+	// wrapped onto two lines.
+	gmailAttributionEnd = regexp.MustCompile(`(?i)\swrote:$`)
+	// gmailOriginalMessageLine matches Outlook's "-----Original Message-----".
+	gmailOriginalMessageLine = regexp.MustCompile(`(?i)^-{2,}\s*original message\s*-{2,}$`)
+	// gmailForwardedLine matches the marker above a forwarded message.
+	gmailForwardedLine = regexp.MustCompile(`(?i)^-{2,}\s*forwarded message\s*-{2,}$`)
+	// gmailQuoteHeaderLine and gmailQuoteDateLine match the header block
+	// Outlook puts above a quoted message.
+	gmailQuoteHeaderLine = regexp.MustCompile(`^\*?(From|De|Von):\*? `)
+	gmailQuoteDateLine   = regexp.MustCompile(`^\*?(Sent|Date|Envoyé|Gesendet):\*? `)
+	// gmailMobileSignature matches the footers mobile mail apps append.
+	gmailMobileSignature = regexp.MustCompile(`(?i)^(sent from my |get outlook for )`)
+)
+
+// gmailQuoteClasses are the HTML classes mail clients put on quoted replies
+// and signatures.
+var gmailQuoteClasses = []string{"gmail_quote", "gmail_signature", "yahoo_quoted", "moz-cite-prefix", "moz-signature"}
+
+// quoteHeaderFollows reports whether an Outlook-style Sent/Date line follows
+// within the next few lines.
+func quoteHeaderFollows(lines []string) bool {
+	for i := 0; i < len(lines) && i < 4; i++ {
+		if gmailQuoteDateLine.MatchString(strings.TrimSpace(lines[i])) {
+			return true
+		}
+	}
+	return false
+}
+
+// stripGmailQuotes removes what a message repeats from earlier in the
+// conversation: everything from the first reply attribution or quoted header
+// block on, ">" quoted lines, the signature after a "-- " delimiter and
+// mobile app footers. A forwarded message is new to the thread and is kept.
+func stripGmailQuotes(body string) string {
+	lines := strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n")
+	var out []string
+	forwarded := false
+scan:
+	for i, raw := range lines {
+		line := strings.TrimSpace(raw)
+		switch {
+		case forwarded:
+		case gmailForwardedLine.MatchString(line):
+			forwarded = true
+		case gmailAttributionLine.MatchString(line), gmailOriginalMessageLine.MatchString(line):
+			break scan
+		case strings.HasPrefix(strings.ToLower(line), "on ") && i+1 < len(lines) && gmailAttributionEnd.MatchString(strings.TrimSpace(lines[i+1])):
+			break scan
+		case gmailQuoteHeaderLine.MatchString(line) && quoteHeaderFollows(lines[i+1:]):
+			break scan
+		case line == "--":
+			break scan
+		case strings.HasPrefix(line, ">"):
+			continue
+		}
+		out = append(out, raw)
+	}
+
+	for len(out) > 0 {
+		last := strings.TrimSpace(out[len(out)-1])
+		if last != "" && !gmailMobileSignature.MatchString(last) {
+			break
+		}
+		out = out[:len(out)-1]
+	}
+	return collapseBlankLines(strings.Join(out, "\n"))
+}
+
+// isHTMLQuote reports whether n holds a quoted reply or signature. Gmail also
+// wraps forwarded messages in a gmail_quote element; those are kept.
+func isHTMLQuote(n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+	if n.Data == "blockquote" {
+		return true
+	}
+	for _, attr := range n.Attr {
+		if attr.Key != "class" {
+			continue
+		}
+		for _, class := range strings.Fields(attr.Val) {
+			for _, quote := range gmailQuoteClasses {
+				if class == quote {
+					return !strings.Contains(htmlNodeText(n), "Forwarded message")
+				}
+			}
+		}
+	}
+	return false
+}
+
+// htmlNodeText returns the text content of n.
+func htmlNodeText(n *html.Node) string {
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return b.String()
+}
+
+// stripHTMLQuotes removes quoted replies and signatures from an HTML body.
+func stripHTMLQuotes(s string) string {
+	doc, err := html.Parse(strings.NewReader(s))
+	if err != nil {
+		return s
+	}
+	var strip func(*html.Node)
+	strip = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; {
+			next := c.NextSibling
+			if isHTMLQuote(c) {
+				n.RemoveChild(c)
+			} else {
+				strip(c)
+			}
+			c = next
+		}
+	}
+	strip(doc)
+
+	var b strings.Builder
+	if err := html.Render(&b, doc); err != nil {
+		return s
+	}
+	return b.String()
+}
+
+// gmailThreadBody returns the new content of a message in a thread.
+func gmailThreadBody(msg *parsedGmailMessage) string {
+	if strings.TrimSpace(msg.Text) != "" {
+		return stripGmailQuotes(msg.Text)
+	}
+	if msg.HTML != "" {
+		return stripGmailQuotes(htmlToText(stripHTMLQuotes(msg.HTML)))
+	}
+	return ""
+}
+
+// gmailParticipant is someone who sent or received a message in a thread.
+type gmailParticipant struct {
+	Address string
+	Name    string
+	Sent    int
+}
+
+// gmailThreadParticipants lists everyone on the From, To and Cc lines of the
+// given messages in order of first appearance, counting the messages each
+// one sent.
+func gmailThreadParticipants(messages []*parsedGmailMessage) []gmailParticipant {
+	var participants []gmailParticipant
+	index := map[string]int{}
+	add := func(header string, sender bool) {
+		addrs, err := mail.ParseAddressList(header)
+		if err != nil {
+			return
+		}
+		for _, a := range addrs {
+			key := strings.ToLower(a.Address)
+			i, ok := index[key]
+			if !ok {
+				i = len(participants)
+				index[key] = i
+				participants = append(participants, gmailParticipant{Address: a.Address})
+			}
+			if participants[i].Name == "" {
+				participants[i].Name = a.Name
+			}
+			if sender {
+				participants[i].Sent++
+			}
+		}
+	}
+	for _, m := range messages {
+		add(gmailHeader(m.Headers, "From"), true)
+		add(gmailHeader(m.Headers, "To"), false)
+		add(gmailHeader(m.Headers, "Cc"), false)
+	}
+	return participants
+}
+
+// GetGmailThread handles the get_gmail_thread tool call
+func GetGmailThread(ctx context.Context, req *mcp.CallToolRequest, input GetGmailThreadInput) (*mcp.CallToolResult, GetGmailThreadOutput, error) {
+	srv, err := utils.NewGmailClient(input.Email)
+	if err != nil {
+		return nil, GetGmailThreadOutput{}, err
+	}
+
+	thread, err := srv.Users.Threads.Get("me", input.ThreadID).
+		Format("full").
+		Context(ctx).
+		Do()
+	if err != nil {
+		return nil, GetGmailThreadOutput{}, fmt.Errorf("failed to get thread: %w", err)
+	}
+
+	messages := thread.Messages
+	sort.SliceStable(messages, func(i, j int) bool { return messages[i].InternalDate < messages[j].InternalDate })
+
+	parsed := make([]*parsedGmailMessage, len(messages))
+	for i, msg := range messages {
+		if parsed[i], err = parseGmailMessage(msg.Payload); err != nil {
+			return nil, GetGmailThreadOutput{}, err
+		}
+	}
+
+	var resp strings.Builder
+	resp.WriteString(fmt.Sprintf("Thread %s (%d messages)\n", thread.Id, len(messages)))
+	if len(parsed) > 0 {
+		resp.WriteString(fmt.Sprintf("Subject: %s\n", gmailHeader(parsed[0].Headers, "Subject")))
+	}
+
+	participants := gmailThreadParticipants(parsed)
+	resp.WriteString(fmt.Sprintf("\nParticipants (%d):\n", len(participants)))
+	for _, p := range participants {
+		who := p.Address
+		if p.Name != "" {
+			who = fmt.Sprintf("%s <%s>", p.Name, p.Address)
+		}
+		resp.WriteString(fmt.Sprintf("- %s (sent %d)\n", who, p.Sent))
+	}
+
+	for i, msg := range messages {
+		p := parsed[i]
+		resp.WriteString(fmt.Sprintf("\n[%d] From: %s\n    Date: %s\n    Message ID: %s\n",
+			i+1, gmailHeader(p.Headers, "From"), gmailHeader(p.Headers, "Date"), msg.Id))
+		if to := gmailHeader(p.Headers, "To"); to != "" {
+			resp.WriteString(fmt.Sprintf("    To: %s\n", to))
+		}
+		if cc := gmailHeader(p.Headers, "Cc"); cc != "" {
+			resp.WriteString(fmt.Sprintf("    Cc: %s\n", cc))
+		}
+		if len(p.Attachments) > 0 {
+			names := make([]string, len(p.Attachments))
+			for j, a := range p.Attachments {
+				names[j] = a.Filename
+			}
+			resp.WriteString(fmt.Sprintf("    Attachments: %s\n", strings.Join(names, ", ")))
+		}
+
+		body := p.Body()
+		if !input.IncludeQuoted {
+			body = gmailThreadBody(p)
+		}
+		if body == "" {
+			body = "(no new text)"
+		}
+		resp.WriteString("\n")
+		resp.WriteString(body)
+		resp.WriteString("\n")
+
+		if resp.Len() > maxInlineContentSize && i < len(messages)-1 {
+			resp.WriteString(fmt.Sprintf("\n[thread truncated: %d more messages]\n", len(messages)-i-1))
+			break
+		}
+	}
+
+	return nil, GetGmailThreadOutput{Thread: resp.String()}, nil
+}
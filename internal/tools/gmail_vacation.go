@@ -0,0 +1,256 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.orx.me/mcp/google-workspace/internal/utils"
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/gmail/v1"
+)
+
+// GetVacationSettingsInput defines input for get_vacation_settings tool
+type GetVacationSettingsInput struct {
+	Email string `json:"email" jsonschema:"Email address to access Gmail"`
+}
+
+// GetVacationSettingsOutput defines output for get_vacation_settings tool
+type GetVacationSettingsOutput struct {
+	Settings string `json:"settings" jsonschema:"Whether the vacation responder is on, its schedule, audience, subject and message"`
+}
+
+// SetVacationSettingsInput defines input for set_vacation_settings tool
+type SetVacationSettingsInput struct {
+	Email               string `json:"email" jsonschema:"Email address to access Gmail"`
+	Enabled             bool   `json:"enabled" jsonschema:"Turn the vacation responder on or off; turning it off keeps the saved message"`
+	StartTime           string `json:"startTime,omitempty" jsonschema:"When auto-replies start, in RFC3339 format (default: now)"`
+	EndTime             string `json:"endTime,omitempty" jsonschema:"When auto-replies stop, in RFC3339 format (default: until turned off)"`
+	Subject             string `json:"subject,omitempty" jsonschema:"Subject of the auto-reply (default: Re: the original subject)"`
+	Body                string `json:"body,omitempty" jsonschema:"Plain text auto-reply"`
+	HTMLBody            string `json:"htmlBody,omitempty" jsonschema:"HTML auto-reply, used instead of body by mail clients that show HTML"`
+	RestrictToContacts  bool   `json:"restrictToContacts,omitempty" jsonschema:"Only reply to senders in the user's contacts"`
+	RestrictToDomain    bool   `json:"restrictToDomain,omitempty" jsonschema:"Only reply to senders in the user's Google Workspace domain"`
+	CreateCalendarEvent bool   `json:"createCalendarEvent,omitempty" jsonschema:"Also create a Calendar out-of-office event for the same period (requires enabled, startTime and endTime)"`
+	DeclineMode         string `json:"declineMode,omitempty" jsonschema:"Which invitations the out-of-office event declines: none, new or all (default new)"`
+}
+
+// SetVacationSettingsOutput defines output for set_vacation_settings tool
+type SetVacationSettingsOutput struct {
+	Result string `json:"result" jsonschema:"Updated vacation settings and the out-of-office event, if one was created"`
+}
+
+// calendarDeclineModes maps declineMode values to Calendar's auto-decline
+// modes for out-of-office events.
+var calendarDeclineModes = map[string]string{
+	"none": "declineNone",
+	"new":  "declineOnlyNewConflictingInvitations",
+	"all":  "declineAllConflictingInvitations",
+}
+
+// parseVacationTime parses an optional RFC3339 time; the empty string is the
+// zero time.
+func parseVacationTime(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %q: expected RFC3339, e.g. 2025-07-01T09:00:00+02:00", name, value)
+	}
+	return t, nil
+}
+
+// newGmailVacationSettings validates input and builds the settings to save.
+// Gmail replaces the whole setting on update, so everything is sent.
+func newGmailVacationSettings(input SetVacationSettingsInput) (*gmail.VacationSettings, error) {
+	start, err := parseVacationTime("startTime", input.StartTime)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseVacationTime("endTime", input.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	if !start.IsZero() && !end.IsZero() && !end.After(start) {
+		return nil, fmt.Errorf("endTime must be after startTime")
+	}
+	if input.Enabled && input.Body == "" && input.HTMLBody == "" {
+		return nil, fmt.Errorf("body or htmlBody is required to turn the vacation responder on")
+	}
+	if input.CreateCalendarEvent && !input.Enabled {
+		return nil, fmt.Errorf("createCalendarEvent requires enabled; an out-of-office event would decline invitations while the responder is off")
+	}
+	if input.CreateCalendarEvent && (start.IsZero() || end.IsZero()) {
+		return nil, fmt.Errorf("startTime and endTime are required to create a calendar event")
+	}
+	if err := checkHeaderValue("subject", input.Subject); err != nil {
+		return nil, err
+	}
+
+	settings := &gmail.VacationSettings{
+		EnableAutoReply:       input.Enabled,
+		ResponseSubject:       input.Subject,
+		ResponseBodyPlainText: input.Body,
+		ResponseBodyHtml:      input.HTMLBody,
+		RestrictToContacts:    input.RestrictToContacts,
+		RestrictToDomain:      input.RestrictToDomain,
+		ForceSendFields:       []string{"EnableAutoReply"},
+	}
+	if !start.IsZero() {
+		settings.StartTime = start.UnixMilli()
+	}
+	if !end.IsZero() {
+		settings.EndTime = end.UnixMilli()
+	}
+	return settings, nil
+}
+
+// newOutOfOfficeEvent builds the Calendar out-of-office event matching a
+// vacation responder.
+func newOutOfOfficeEvent(input SetVacationSettingsInput) (*calendar.Event, error) {
+	mode := input.DeclineMode
+	if mode == "" {
+		mode = "new"
+	}
+	autoDecline, ok := calendarDeclineModes[mode]
+	if !ok {
+		return nil, fmt.Errorf("invalid declineMode %q: expected none, new or all", input.DeclineMode)
+	}
+
+	summary := input.Subject
+	if summary == "" {
+		summary = "Out of office"
+	}
+	message := input.Body
+	if message == "" {
+		message = htmlToText(input.HTMLBody)
+	}
+
+	props := &calendar.EventOutOfOfficeProperties{AutoDeclineMode: autoDecline}
+	if autoDecline != "declineNone" {
+		props.DeclineMessage = message
+	}
+	return &calendar.Event{
+		EventType:             "outOfOffice",
+		Summary:               summary,
+		Start:                 &calendar.EventDateTime{DateTime: input.StartTime},
+		End:                   &calendar.EventDateTime{DateTime: input.EndTime},
+		Transparency:          "opaque",
+		OutOfOfficeProperties: props,
+	}, nil
+}
+
+// formatVacationTime renders a vacation start or end time in milliseconds.
+func formatVacationTime(ms int64, unset string) string {
+	if ms == 0 {
+		return unset
+	}
+	return time.UnixMilli(ms).UTC().Format(time.RFC3339)
+}
+
+// describeGmailVacation renders vacation settings.
+func describeGmailVacation(v *gmail.VacationSettings) string {
+	var b strings.Builder
+	status := "off"
+	if v.EnableAutoReply {
+		status = "on"
+	}
+	b.WriteString(fmt.Sprintf("Vacation responder: %s\n", status))
+	b.WriteString(fmt.Sprintf("  Start: %s\n", formatVacationTime(v.StartTime, "(immediately)")))
+	b.WriteString(fmt.Sprintf("  End: %s\n", formatVacationTime(v.EndTime, "(until turned off)")))
+
+	audience := "everyone"
+	switch {
+	case v.RestrictToContacts && v.RestrictToDomain:
+		audience = "contacts in the domain"
+	case v.RestrictToContacts:
+		audience = "contacts only"
+	case v.RestrictToDomain:
+		audience = "the domain only"
+	}
+	b.WriteString(fmt.Sprintf("  Replies to: %s\n", audience))
+	if v.ResponseSubject != "" {
+		b.WriteString(fmt.Sprintf("  Subject: %s\n", v.ResponseSubject))
+	}
+	if v.ResponseBodyPlainText != "" {
+		b.WriteString("\nMessage:\n")
+		b.WriteString(v.ResponseBodyPlainText)
+		b.WriteString("\n")
+	}
+	if v.ResponseBodyHtml != "" {
+		b.WriteString("\nHTML message (converted to text):\n")
+		b.WriteString(htmlToText(v.ResponseBodyHtml))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// GetVacationSettings handles the get_vacation_settings tool call
+func GetVacationSettings(ctx context.Context, req *mcp.CallToolRequest, input GetVacationSettingsInput) (*mcp.CallToolResult, GetVacationSettingsOutput, error) {
+	srv, err := utils.NewGmailClient(input.Email)
+	if err != nil {
+		return nil, GetVacationSettingsOutput{}, err
+	}
+
+	v, err := srv.Users.Settings.GetVacation("me").Context(ctx).Do()
+	if err != nil {
+		return nil, GetVacationSettingsOutput{}, fmt.Errorf("failed to get vacation settings: %w", err)
+	}
+
+	return nil, GetVacationSettingsOutput{Settings: describeGmailVacation(v)}, nil
+}
+
+// SetVacationSettings handles the set_vacation_settings tool call. Turning the
+// responder off without a new message keeps the saved one, so it can be
+// turned back on later unchanged.
+func SetVacationSettings(ctx context.Context, req *mcp.CallToolRequest, input SetVacationSettingsInput) (*mcp.CallToolResult, SetVacationSettingsOutput, error) {
+	settings, err := newGmailVacationSettings(input)
+	if err != nil {
+		return nil, SetVacationSettingsOutput{}, err
+	}
+	var event *calendar.Event
+	if input.CreateCalendarEvent {
+		if event, err = newOutOfOfficeEvent(input); err != nil {
+			return nil, SetVacationSettingsOutput{}, err
+		}
+	}
+
+	srv, err := utils.NewGmailClient(input.Email, gmailSettingsScopes...)
+	if err != nil {
+		return nil, SetVacationSettingsOutput{}, err
+	}
+
+	if !input.Enabled && input.Body == "" && input.HTMLBody == "" {
+		current, err := srv.Users.Settings.GetVacation("me").Context(ctx).Do()
+		if err != nil {
+			return nil, SetVacationSettingsOutput{}, fmt.Errorf("failed to get vacation settings: %w", err)
+		}
+		settings = current
+		settings.EnableAutoReply = false
+		settings.ForceSendFields = []string{"EnableAutoReply"}
+	}
+
+	updated, err := srv.Users.Settings.UpdateVacation("me", settings).Context(ctx).Do()
+	if err != nil {
+		return nil, SetVacationSettingsOutput{}, fmt.Errorf("failed to update vacation settings: %w", err)
+	}
+
+	resp := "Vacation settings updated:\n" + describeGmailVacation(updated)
+	if event != nil {
+		cal, err := utils.NewCalendarClient(input.Email)
+		if err != nil {
+			return nil, SetVacationSettingsOutput{}, err
+		}
+		created, err := cal.Events.Insert("primary", event).Context(ctx).Do()
+		if err != nil {
+			return nil, SetVacationSettingsOutput{}, fmt.Errorf("updated vacation settings but failed to create out-of-office event: %w", err)
+		}
+		resp += fmt.Sprintf("\nOut-of-office event created:\n  ID: %s\n  Start: %s\n  End: %s\n  Link: %s\n",
+			created.Id, created.Start.DateTime, created.End.DateTime, created.HtmlLink)
+	}
+
+	return nil, SetVacationSettingsOutput{Result: resp}, nil
+}
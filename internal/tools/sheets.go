@@ -7,6 +7,7 @@ import (
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"go.orx.me/mcp/google-workspace/internal/utils"
+	"google.golang.org/api/drive/v3"
 	"google.golang.org/api/sheets/v4"
 )
 
@@ -14,6 +15,7 @@ import (
 type ListSpreadsheetsInput struct {
 	Email      string `json:"email" jsonschema:"Email address to access Sheets"`
 	MaxResults int64  `json:"maxResults,omitempty" jsonschema:"Maximum number of spreadsheets to return (default 10)"`
+	DriveID    string `json:"driveId,omitempty" jsonschema:"Optional shared drive ID to limit the listing to"`
 }
 
 // ListSpreadsheetsOutput defines output for list_spreadsheets tool
@@ -76,6 +78,7 @@ type CreateSpreadsheetInput struct {
 	Email      string   `json:"email" jsonschema:"Email address to access Sheets"`
 	Title      string   `json:"title" jsonschema:"Spreadsheet title"`
 	SheetNames []string `json:"sheetNames,omitempty" jsonschema:"Optional list of sheet names to create"`
+	ParentID   string   `json:"parentId,omitempty" jsonschema:"Optional folder or shared drive ID to create the spreadsheet in"`
 }
 
 // CreateSpreadsheetOutput defines output for create_spreadsheet tool
@@ -97,11 +100,11 @@ func ListSpreadsheets(ctx context.Context, req *mcp.CallToolRequest, input ListS
 	}
 
 	// Query for Google Sheets MIME type
-	query := "mimeType = 'application/vnd.google-apps.spreadsheet' and trashed = false"
+	query := (&driveQuery{}).MimeType("application/vnd.google-apps.spreadsheet").Trashed(false)
 
-	files, err := driveSrv.Files.List().
+	files, err := allDrivesList(driveSrv.Files.List(), input.DriveID).
 		PageSize(maxResults).
-		Q(query).
+		Q(query.String()).
 		Fields("files(id, name, modifiedTime)").
 		Do()
 	if err != nil {
@@ -262,12 +265,44 @@ func CreateSpreadsheet(ctx context.Context, req *mcp.CallToolRequest, input Crea
 		return nil, CreateSpreadsheetOutput{}, fmt.Errorf("failed to create spreadsheet: %w", err)
 	}
 
+	// The Sheets API always creates in the root of My Drive, so move the new
+	// file with the Drive API when a folder or shared drive was requested.
+	if input.ParentID != "" {
+		if err := moveSpreadsheet(input.Email, created.SpreadsheetId, input.ParentID); err != nil {
+			return nil, CreateSpreadsheetOutput{}, fmt.Errorf("spreadsheet %s created but could not be moved: %w", created.SpreadsheetId, err)
+		}
+	}
+
 	resp := fmt.Sprintf("Spreadsheet created successfully:\n  Title: %s\n  ID: %s\n  URL: %s",
 		created.Properties.Title, created.SpreadsheetId, created.SpreadsheetUrl)
 
 	return nil, CreateSpreadsheetOutput{Result: resp}, nil
 }
 
+// moveSpreadsheet moves a spreadsheet out of its current parents into parentID,
+// which may be a folder or a shared drive.
+func moveSpreadsheet(email, spreadsheetID, parentID string) error {
+	driveSrv, err := utils.NewDriveClient(email)
+	if err != nil {
+		return err
+	}
+
+	file, err := driveSrv.Files.Get(spreadsheetID).
+		SupportsAllDrives(true).
+		Fields("parents").
+		Do()
+	if err != nil {
+		return err
+	}
+
+	_, err = driveSrv.Files.Update(spreadsheetID, &drive.File{}).
+		AddParents(parentID).
+		RemoveParents(strings.Join(file.Parents, ",")).
+		SupportsAllDrives(true).
+		Do()
+	return err
+}
+
 // RegisterSheetsTools registers all Sheets-related tools with the MCP server
 func RegisterSheetsTools(server *mcp.Server) {
 	mcp.AddTool(server, &mcp.Tool{
@@ -104,8 +104,8 @@ func TestSheetsRequiredFieldsHaveRequiredTag(t *testing.T) {
 // TestSheetsOptionalFieldsNotRequired verifies that optional fields don't have required tag
 func TestSheetsOptionalFieldsNotRequired(t *testing.T) {
 	optionalFields := map[string][]string{
-		"ListSpreadsheetsInput":  {"MaxResults"},
-		"CreateSpreadsheetInput": {"SheetNames"},
+		"ListSpreadsheetsInput":  {"MaxResults", "DriveID"},
+		"CreateSpreadsheetInput": {"SheetNames", "ParentID"},
 	}
 
 	inputStructs := []interface{}{
@@ -3,6 +3,7 @@ package utils
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 
 	"butterfly.orx.me/core/log"
@@ -47,17 +48,23 @@ func DefaultClient() (*admin.Service, error) {
 	return newClient(sa, adminEmail)
 }
 
-func NewGmailClient(email string) (*gmail.Service, error) {
+// NewGmailClient returns a Gmail client impersonating email. It requests the
+// read-only scope unless other scopes are given; tools that change a mailbox
+// pass exactly the scopes they need.
+func NewGmailClient(email string, scopes ...string) (*gmail.Service, error) {
 	sa, err := defaultServiceAccount()
 	if err != nil {
 		return nil, err
 	}
 	// Create client using service account and admin email
-	return newGmailClient(sa, email)
+	return newGmailClient(sa, email, scopes...)
 }
 
-func newGmailClient(sa []byte, email string) (*gmail.Service, error) {
-	ts, err := tokenSource(sa, email, gmail.GmailReadonlyScope)
+func newGmailClient(sa []byte, email string, scopes ...string) (*gmail.Service, error) {
+	if len(scopes) == 0 {
+		scopes = []string{gmail.GmailReadonlyScope}
+	}
+	ts, err := tokenSource(sa, email, scopes...)
 	if err != nil {
 		return nil, err
 	}
@@ -144,6 +151,21 @@ func newDriveClient(sa []byte, email string) (*drive.Service, error) {
 	return srv, nil
 }
 
+// NewDriveHTTPClient returns an HTTP client authorized for Drive as email. It is
+// used for URLs the generated client has no call for, such as revision export links.
+func NewDriveHTTPClient(email string) (*http.Client, error) {
+	sa, err := defaultServiceAccount()
+	if err != nil {
+		return nil, err
+	}
+
+	ts, err := tokenSource(sa, email, drive.DriveScope)
+	if err != nil {
+		return nil, err
+	}
+	return oauth2.NewClient(context.Background(), ts), nil
+}
+
 func NewSheetsClient(email string) (*sheets.Service, error) {
 	sa, err := defaultServiceAccount()
 	if err != nil {
@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SandboxRoot returns the absolute, symlink-resolved directory named by
+// MCP_SANDBOX_DIR. Tools that read or write local files are confined to it.
+func SandboxRoot() (string, error) {
+	dir := os.Getenv("MCP_SANDBOX_DIR")
+	if dir == "" {
+		return "", fmt.Errorf("MCP_SANDBOX_DIR environment variable not set")
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve sandbox directory: %w", err)
+	}
+	root, err := filepath.EvalSymlinks(abs)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve sandbox directory: %w", err)
+	}
+	return root, nil
+}
+
+// SandboxPath resolves p inside the sandbox root. Relative paths are taken
+// relative to the root; absolute paths must already point inside it. The
+// deepest existing ancestor of the result is resolved through symlinks so a
+// link inside the sandbox cannot be used to escape it.
+func SandboxPath(p string) (string, error) {
+	root, err := SandboxRoot()
+	if err != nil {
+		return "", err
+	}
+
+	target := p
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(root, target)
+	}
+	target = filepath.Clean(target)
+
+	if !within(root, target) {
+		return "", fmt.Errorf("path %q is outside the sandbox directory", p)
+	}
+
+	// Walk up to the first component that exists and make sure it does not
+	// resolve to somewhere outside the root.
+	existing := target
+	for {
+		if _, err := os.Lstat(existing); err == nil {
+			break
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			break
+		}
+		existing = parent
+	}
+	resolved, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve path %q: %w", p, err)
+	}
+	if !within(root, resolved) {
+		return "", fmt.Errorf("path %q is outside the sandbox directory", p)
+	}
+
+	return target, nil
+}
+
+// within reports whether path is root or a descendant of it.
+func within(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestSandboxPathMissingEnv verifies error message when MCP_SANDBOX_DIR is not set
+func TestSandboxPathMissingEnv(t *testing.T) {
+	t.Setenv("MCP_SANDBOX_DIR", "")
+
+	_, err := SandboxPath("file.txt")
+	if err == nil {
+		t.Fatal("Expected error when MCP_SANDBOX_DIR is not set, got nil")
+	}
+
+	if !strings.Contains(err.Error(), "MCP_SANDBOX_DIR") {
+		t.Errorf("Error message should contain 'MCP_SANDBOX_DIR', got: %s", err.Error())
+	}
+}
+
+// TestSandboxPath verifies that paths are confined to the sandbox directory
+func TestSandboxPath(t *testing.T) {
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to resolve temp dir: %v", err)
+	}
+	t.Setenv("MCP_SANDBOX_DIR", root)
+
+	outside := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(root, "escape")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	testCases := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{"relative file", "report.csv", filepath.Join(root, "report.csv"), false},
+		{"nested new dirs", "a/b/c.txt", filepath.Join(root, "a", "b", "c.txt"), false},
+		{"root itself", ".", root, false},
+		{"absolute inside", filepath.Join(root, "x.txt"), filepath.Join(root, "x.txt"), false},
+		{"parent traversal", "../secret", "", true},
+		{"traversal after clean", "a/../../secret", "", true},
+		{"absolute outside", "/etc/passwd", "", true},
+		{"symlink escape", "escape/file.txt", "", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := SandboxPath(tc.path)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("Expected error for %q, got path %q", tc.path, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error for %q: %v", tc.path, err)
+			}
+			if got != tc.want {
+				t.Errorf("SandboxPath(%q) = %q, want %q", tc.path, got, tc.want)
+			}
+		})
+	}
+}